@@ -0,0 +1,211 @@
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"runtime"
+	"sync"
+)
+
+// IndexedError is returned by batch functions and points to the first
+// input that failed.
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexedError) Error() string {
+	return fmt.Sprintf("input %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// batchSecurity is the bit security of batched subgroup checks.
+const batchSecurity = 64
+
+// Batched subgroup check computes S = sum(r_i * P_i) for random r_i and tests
+// whether q * S is zero. A point outside of the subgroup has a component of
+// order l for some prime l dividing the cofactor, and this component survives
+// in S with probability at least 1 - 1/l. Cofactors of both groups have small
+// prime factors, so a single combination is not enough. Instead each small
+// prime l is checked in ceil(batchSecurity / log2(l)) rounds, and primes are
+// merged into shared rounds by drawing r_i uniformly modulo their product.
+// If the cofactor has a large prime factor, the first round is extended with
+// batchSecurity random bits to cover it.
+var batchRangesG1 = batchRanges(cofactorG1Factors, false)
+var batchRangesG2 = batchRanges(cofactorG2Factors, true)
+
+func batchRanges(factors []uint64, large bool) []*big.Int {
+	rounds := make([]int, len(factors))
+	max := 0
+	for i, l := range factors {
+		rounds[i] = int(math.Ceil(batchSecurity / math.Log2(float64(l))))
+		if rounds[i] > max {
+			max = rounds[i]
+		}
+	}
+	ranges := make([]*big.Int, max)
+	for j := 0; j < max; j++ {
+		ranges[j] = big.NewInt(1)
+		for i, l := range factors {
+			if rounds[i] > j {
+				ranges[j].Mul(ranges[j], new(big.Int).SetUint64(l))
+			}
+		}
+	}
+	if large {
+		ranges[0].Lsh(ranges[0], batchSecurity)
+	}
+	return ranges
+}
+
+// parallel calls f for each index in [0, n) with inputs split into chunks.
+// Every chunk is processed with a worker id in [0, number of workers). It
+// returns an IndexedError for the smallest failing index.
+func parallel(n int, f func(worker, i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	var wg sync.WaitGroup
+	errs := make([]*IndexedError, workers)
+	chunk := (n + workers - 1) / workers
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w * chunk; i < (w+1)*chunk && i < n; i++ {
+				if err := f(w, i); err != nil {
+					errs[w] = &IndexedError{i, err}
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func randScalarsInRange(r *big.Int, n int) ([]*big.Int, error) {
+	scalars := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		s, err := rand.Int(rand.Reader, r)
+		if err != nil {
+			return nil, err
+		}
+		scalars[i] = s
+	}
+	return scalars, nil
+}
+
+// BatchFromCompressed decodes compressed points in parallel and checks that
+// all of them are in correct subgroup with random linear combinations. On
+// failure returned error is an *IndexedError pointing to the first invalid
+// input.
+func (g *G1) BatchFromCompressed(in [][]byte) ([]*PointG1, error) {
+	n := len(in)
+	points := make([]*PointG1, n)
+	groups := make([]*G1, runtime.NumCPU())
+	for i := range groups {
+		groups[i] = NewG1(g.f)
+	}
+	if err := parallel(n, func(w, i int) error {
+		if len(in[i]) != 48 {
+			return ErrInvalidLength
+		}
+		p, err := groups[w].FromCompressedUnchecked(in[i])
+		points[i] = p
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	// batching pays off only if there are more points than rounds
+	if n > len(batchRangesG1) && parallel(len(batchRangesG1), func(w, j int) error {
+		scalars, err := randScalarsInRange(batchRangesG1[j], n)
+		if err != nil {
+			return err
+		}
+		s := &PointG1{}
+		if _, err := groups[w].MultiExp(s, points, scalars); err != nil {
+			return err
+		}
+		if !groups[w].isTorsionFree(s) {
+			return ErrNotInSubgroup
+		}
+		return nil
+	}) == nil {
+		return points, nil
+	}
+	// check points one by one, also to find the invalid one
+	if err := parallel(n, func(w, i int) error {
+		if !groups[w].isTorsionFree(points[i]) {
+			return ErrNotInSubgroup
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// BatchFromCompressed decodes compressed points in parallel and checks that
+// all of them are in correct subgroup with random linear combinations. On
+// failure returned error is an *IndexedError pointing to the first invalid
+// input.
+func (g *G2) BatchFromCompressed(in [][]byte) ([]*PointG2, error) {
+	n := len(in)
+	points := make([]*PointG2, n)
+	groups := make([]*G2, runtime.NumCPU())
+	for i := range groups {
+		groups[i] = NewG2(NewFp2(g.f.f))
+	}
+	if err := parallel(n, func(w, i int) error {
+		if len(in[i]) != 96 {
+			return ErrInvalidLength
+		}
+		p, err := groups[w].FromCompressedUnchecked(in[i])
+		points[i] = p
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	// batching pays off only if there are more points than rounds
+	if n > len(batchRangesG2) && parallel(len(batchRangesG2), func(w, j int) error {
+		scalars, err := randScalarsInRange(batchRangesG2[j], n)
+		if err != nil {
+			return err
+		}
+		s := &PointG2{}
+		if _, err := groups[w].MultiExp(s, points, scalars); err != nil {
+			return err
+		}
+		if !groups[w].isTorsionFree(s) {
+			return ErrNotInSubgroup
+		}
+		return nil
+	}) == nil {
+		return points, nil
+	}
+	// check points one by one, also to find the invalid one
+	if err := parallel(n, func(w, i int) error {
+		if !groups[w].isTorsionFree(points[i]) {
+			return ErrNotInSubgroup
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
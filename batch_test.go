@@ -0,0 +1,144 @@
+package bls
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestBatchRanges(t *testing.T) {
+	for _, v := range []struct {
+		factors []uint64
+		ranges  []*big.Int
+	}{
+		{cofactorG1Factors, batchRangesG1},
+		{cofactorG2Factors, batchRangesG2},
+	} {
+		// every factor should get at least batchSecurity bits of randomness
+		for _, l := range v.factors {
+			bits := 0.0
+			for _, r := range v.ranges {
+				if new(big.Int).Mod(r, new(big.Int).SetUint64(l)).Sign() == 0 {
+					bits += math.Log2(float64(l))
+				}
+			}
+			if bits < batchSecurity {
+				t.Fatalf("not enough rounds for factor %d", l)
+			}
+		}
+	}
+	if batchRangesG2[0].BitLen() <= batchSecurity {
+		t.Fatalf("large factor of g2 cofactor is not covered")
+	}
+}
+
+func TestG1BatchFromCompressed(t *testing.T) {
+	g1 := NewG1(NewFp())
+	size := len(batchRangesG1) + 10
+	in := make([][]byte, size)
+	expected := make([]*PointG1, size)
+	for i := 0; i < size; i++ {
+		expected[i] = g1.MulScalar(&PointG1{}, &G1One, randScalar(q))
+		in[i] = g1.ToCompressed(expected[i])
+	}
+	in[3] = g1.ToCompressed(g1.Zero())
+	expected[3] = g1.Zero()
+	for _, in := range [][][]byte{in, in[:5], {}} {
+		points, err := g1.BatchFromCompressed(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range in {
+			if !g1.Equal(points[i], expected[i]) {
+				t.Fatalf("bad batch decoding at %d", i)
+			}
+		}
+	}
+	offCurve, offSubgroup := invalidCompressedG1(g1)
+	for _, v := range []struct {
+		index int
+		in    []byte
+		err   error
+	}{
+		{7, offSubgroup, ErrNotInSubgroup},
+		{size - 1, offSubgroup, ErrNotInSubgroup},
+		{2, offCurve, ErrNotOnCurve},
+		{4, in[4][:47], ErrInvalidLength},
+	} {
+		for _, in := range [][][]byte{in, in[:v.index+1]} {
+			invalid := append([][]byte{}, in...)
+			invalid[v.index] = v.in
+			_, err := g1.BatchFromCompressed(invalid)
+			indexed := &IndexedError{}
+			if !errors.As(err, &indexed) || indexed.Index != v.index || !errors.Is(err, v.err) {
+				t.Fatalf("expected %v at %d, got %v", v.err, v.index, err)
+			}
+		}
+	}
+}
+
+func TestG2BatchFromCompressed(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	size := len(batchRangesG2) + 5
+	in := make([][]byte, size)
+	expected := make([]*PointG2, size)
+	for i := 0; i < size; i++ {
+		expected[i] = g2.MulScalar(&PointG2{}, &G2One, randScalar(q))
+		in[i] = g2.ToCompressed(expected[i])
+	}
+	for _, in := range [][][]byte{in, in[:5]} {
+		points, err := g2.BatchFromCompressed(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range in {
+			if !g2.Equal(points[i], expected[i]) {
+				t.Fatalf("bad batch decoding at %d", i)
+			}
+		}
+	}
+	offCurve, offSubgroup := invalidCompressedG2(g2)
+	for _, v := range []struct {
+		index int
+		in    []byte
+		err   error
+	}{
+		{1, offSubgroup, ErrNotInSubgroup},
+		{2, offCurve, ErrNotOnCurve},
+	} {
+		for _, in := range [][][]byte{in, in[:v.index+1]} {
+			invalid := append([][]byte{}, in...)
+			invalid[v.index] = v.in
+			_, err := g2.BatchFromCompressed(invalid)
+			indexed := &IndexedError{}
+			if !errors.As(err, &indexed) || indexed.Index != v.index || !errors.Is(err, v.err) {
+				t.Fatalf("expected %v at %d, got %v", v.err, v.index, err)
+			}
+		}
+	}
+}
+
+func BenchmarkG1BatchFromCompressed(t *testing.B) {
+	g1 := NewG1(NewFp())
+	in := make([][]byte, 1000)
+	for i := 0; i < len(in); i++ {
+		in[i] = g1.ToCompressed(g1.MulScalar(&PointG1{}, &G1One, randScalar(q)))
+	}
+	t.Run("Batch", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			if _, err := g1.BatchFromCompressed(in); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("Sequential", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			for j := 0; j < len(in); j++ {
+				if _, err := g1.FromCompressed(in[j]); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	})
+}
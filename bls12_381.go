@@ -1,3 +1,11 @@
+// Package bls implements fields, groups, pairing and hashing to curve of
+// BLS12-381.
+//
+// Base field, extension field and group operations are methods of context
+// types, Fp, Fp2, G1 and G2, which hold temporary buffers, as in
+// fp.Mul(c, a, b). Scalar field elements Fr deliberately follow the math/big
+// style instead, e.Mul(a, b), since scalar arithmetic needs no buffers and
+// is mostly used by protocol code with short lived values.
 package bls
 
 import "math/big"
@@ -31,18 +39,17 @@ var negativeOne2 = &Fe2{
 // 2^-1
 var twoInv = &Fe{0x1804000000015554, 0x855000053ab00001, 0x633cb57c253c276f, 0x6e22d1ec31ebb502, 0xd3916126f2d14ca2, 0x17fbb8571a006596}
 
-// (q-3) / 4
-var pMinus3Over4 = new(big.Int).SetBytes(
-	bytes_(-1, "0x680447a8e5ff9a692c6e9ed90d2eb35d91dd2e13ce144afd9cc34a83dac3d8907aaffffac54ffffee7fbfffffffeaaa"))
-
-// (q-3) / 4
+// (p+1) / 4
 var pPlus1Over4 = new(big.Int).SetBytes(
 	bytes_(-1, "0x680447a8e5ff9a692c6e9ed90d2eb35d91dd2e13ce144afd9cc34a83dac3d8907aaffffac54ffffee7fbfffffffeaab"))
 
-// (q-1) / 2
+// (p-1) / 2
 var pMinus1Over2 = new(big.Int).SetBytes(
 	bytes_(48, "0xd0088f51cbff34d258dd3db21a5d66bb23ba5c279c2895fb39869507b587b120f55ffff58a9ffffdcff7fffffffd555"))
 
+// (p-1) / 2 in normal form
+var halfModulus = &Fe{0xdcff7fffffffd555, 0x0f55ffff58a9ffff, 0xb39869507b587b12, 0xb23ba5c279c2895f, 0x258dd3db21a5d66b, 0x0d0088f51cbff34d}
+
 // -1
 var nonResidue1 = &Fe{0x43f5fffffffcaaae, 0x32b7fff2ed47fffd, 0x07e83a49a2e99d69, 0xeca8f3318332bb7a, 0xef148d1ea0f4c069, 0x040ab3263eff0206}
 
@@ -77,6 +84,21 @@ var cofactorG1 = new(big.Int).SetBytes(
 var cofactorG2 = new(big.Int).SetBytes(
 	bytes_(-1, "5d543a95414e7f1091d50792876a202cd91de4547085abaa68a205b2e5a7ddfa628f1cb4d9e82ef21537e293a6691ae1616ec6e786f0c70cf1c38e31c7238e5"))
 
+// small prime factors of cofactor g1, cofactorG1 = 3 * 11^2 * 10177^2 * 859267^2 * 52437899^2
+var cofactorG1Factors = []uint64{3, 11, 10177, 859267, 52437899}
+
+// small prime factors of cofactor g2, rest of cofactorG2 is a 448 bit prime
+// cofactorG2 = 13^2 * 23^2 * 2713 * 11953 * 262069 * p448
+var cofactorG2Factors = []uint64{13, 23, 2713, 11953, 262069}
+
+// effective cofactor g1 for hash to curve, 1 - x
+var cofactorEffG1 = new(big.Int).SetBytes(
+	bytes_(-1, "0xd201000000010001"))
+
+// effective cofactor g2 for hash to curve
+var cofactorEffG2 = new(big.Int).SetBytes(
+	bytes_(-1, "0xbc69f08f2ee75b3584c6a0ea91b352888e2a8e9145ad7689986ff031508ffe1329c2f178731db956d82bf015d1212b02ec0ec69d7477c1ae954cbc06689f6a359894c0adebbf6b4e8020005aaa95551"))
+
 // point at infinity in G1
 var infinity = &PointG1{
 	Fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
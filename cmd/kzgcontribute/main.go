@@ -0,0 +1,63 @@
+// Command kzgcontribute adds a contribution to a powers of tau batch
+// transcript in JSON format of Ethereum KZG ceremony. Input transcript is
+// verified before contributing.
+//
+//	kzgcontribute -in transcript.json -out contribution.json -id name
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kilic/bls12-381/kzg"
+)
+
+func main() {
+	in := flag.String("in", "", "input transcript file")
+	out := flag.String("out", "", "output transcript file")
+	id := flag.String("id", "", "participant id")
+	flag.Parse()
+	if *in == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*in, *out, *id); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, id string) error {
+	f, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	b, err := kzg.LoadBatchTranscript(bufio.NewReader(f))
+	f.Close()
+	if err != nil {
+		return err
+	}
+	if err := b.Verify(nil); err != nil {
+		return err
+	}
+	if err := b.Contribute(id, nil); err != nil {
+		return err
+	}
+	f, err = os.Create(out)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := json.NewEncoder(w).Encode(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
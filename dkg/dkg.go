@@ -0,0 +1,552 @@
+// Package dkg implements distributed key generation of Gennaro, Jarecki,
+// Krawczyk and Rabin where n participants jointly generate a BLS key pair
+// whose secret key is shared with threshold t and never known by any party.
+// https://link.springer.com/article/10.1007/s00145-006-0347-3
+//
+// Protocol runs in rounds. At each round a participant emits messages with
+// Next and processes messages of other participants with Process. Broadcast
+// messages must be delivered to all participants consistently and share
+// messages must be delivered over private channels.
+package dkg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Kind is type of a protocol message.
+type Kind uint8
+
+const (
+	// KindDeal broadcasts Pedersen commitments to polynomials of dealer.
+	KindDeal Kind = iota + 1
+	// KindShare privately sends a share and a blinding share to a participant.
+	KindShare
+	// KindComplaint broadcasts a complaint against a dealer whose share does
+	// not match its commitments.
+	KindComplaint
+	// KindJustification broadcasts share of a complaining participant.
+	KindJustification
+	// KindCommitment broadcasts Feldman commitments to polynomial of dealer.
+	KindCommitment
+	// KindPublicComplaint broadcasts a share that does not match Feldman
+	// commitments of its dealer.
+	KindPublicComplaint
+	// KindReveal broadcasts share of a dealer whose secret is reconstructed.
+	KindReveal
+)
+
+const finalRound = 7
+
+// round returns the round in which messages of the kind are sent.
+func (k Kind) round() int {
+	if k <= KindShare {
+		return 1
+	}
+	return int(k) - 1
+}
+
+func (k Kind) String() string {
+	switch k {
+	case KindDeal:
+		return "deal"
+	case KindShare:
+		return "share"
+	case KindComplaint:
+		return "complaint"
+	case KindJustification:
+		return "justification"
+	case KindCommitment:
+		return "commitment"
+	case KindPublicComplaint:
+		return "public complaint"
+	case KindReveal:
+		return "reveal"
+	}
+	return fmt.Sprintf("kind(%d)", uint8(k))
+}
+
+// Message is a protocol message. Points are in compressed form and scalars
+// are 32 bytes big endian.
+type Message struct {
+	Kind Kind   `json:"kind"`
+	From uint64 `json:"from"`
+	// To is the recipient of a share message and zero for broadcasts.
+	To uint64 `json:"to"`
+	// Target is the dealer that a complaint or a reveal is about, or the
+	// complaining participant that a justification is for.
+	Target      uint64   `json:"target,omitempty"`
+	Commitments [][]byte `json:"commitments,omitempty"`
+	Share       []byte   `json:"share,omitempty"`
+	Blinding    []byte   `json:"blinding,omitempty"`
+}
+
+// Errors returned by Participant.
+var (
+	ErrInvalidMessage    = errors.New("invalid message")
+	ErrUnexpectedMessage = errors.New("unexpected message")
+	ErrDuplicateMessage  = errors.New("duplicate message")
+	ErrNotFinished       = errors.New("protocol is not finished")
+)
+
+// Result is output of the protocol for a participant.
+type Result struct {
+	Index uint64
+	// Share is the secret key share of the participant.
+	Share *bls.Fr
+	// PublicKey is the public key of the shared secret key.
+	PublicKey *bls.PointG1
+	// Qualified are indexes of dealers whose secrets add up to the shared
+	// secret key.
+	Qualified []uint64
+}
+
+type share struct {
+	value, blinding *bls.Fr
+}
+
+type complaint struct {
+	from, dealer uint64
+}
+
+// Participant is state of a participant in a protocol run.
+type Participant struct {
+	index uint64
+	n, t  int
+	r     io.Reader
+	g     *bls.G1
+	round int
+
+	// own polynomials
+	a, b []*bls.Fr
+
+	pedersen         map[uint64][]*bls.PointG1
+	feldman          map[uint64][]*bls.PointG1
+	shares           map[uint64]*share
+	complaints       map[complaint]bool
+	justifications   map[complaint]*share
+	publicComplaints map[complaint]*share
+	reveals          map[complaint]*share
+
+	qualified   []uint64
+	reconstruct map[uint64]bool
+}
+
+// NewParticipant creates participant with index in range [1, n] of a protocol
+// with n participants and threshold t. Randomness is read from r, if r is nil
+// crypto/rand is used.
+func NewParticipant(index uint64, t, n int, r io.Reader) (*Participant, error) {
+	if t < 1 || t > n {
+		return nil, fmt.Errorf("threshold should be in range of [1, %d]", n)
+	}
+	if index < 1 || index > uint64(n) {
+		return nil, fmt.Errorf("index should be in range of [1, %d]", n)
+	}
+	return &Participant{
+		index:            index,
+		n:                n,
+		t:                t,
+		r:                r,
+		g:                bls.NewG1(bls.NewFp()),
+		pedersen:         make(map[uint64][]*bls.PointG1),
+		feldman:          make(map[uint64][]*bls.PointG1),
+		shares:           make(map[uint64]*share),
+		complaints:       make(map[complaint]bool),
+		justifications:   make(map[complaint]*share),
+		publicComplaints: make(map[complaint]*share),
+		reveals:          make(map[complaint]*share),
+		reconstruct:      make(map[uint64]bool),
+	}, nil
+}
+
+// Index returns index of the participant.
+func (p *Participant) Index() uint64 {
+	return p.index
+}
+
+// Next closes the current round, evaluating messages processed so far, and
+// returns messages of the next round. After the last round Next returns no
+// messages and Finalize should be called.
+func (p *Participant) Next() ([]*Message, error) {
+	if p.round >= finalRound-1 {
+		return nil, nil
+	}
+	p.round++
+	switch p.round {
+	case 1:
+		return p.deal()
+	case 2:
+		return p.complain(), nil
+	case 3:
+		return p.justify(), nil
+	case 4:
+		p.qualify()
+		return p.commit(), nil
+	case 5:
+		return p.complainPublicly(), nil
+	case 6:
+		p.evaluatePublicComplaints()
+		return p.reveal(), nil
+	}
+	return nil, nil
+}
+
+// Process validates and stores a message of the current round. Messages of
+// rounds that are already closed are rejected.
+func (p *Participant) Process(m *Message) error {
+	if m.Kind < KindDeal || m.Kind > KindReveal {
+		return fmt.Errorf("%w: unknown kind %d", ErrInvalidMessage, m.Kind)
+	}
+	if m.Kind.round() < p.round || p.round >= finalRound {
+		return fmt.Errorf("%w: %s message in round %d", ErrUnexpectedMessage, m.Kind, p.round)
+	}
+	if !p.isParticipant(m.From) || m.From == p.index {
+		return fmt.Errorf("%w: bad sender %d", ErrInvalidMessage, m.From)
+	}
+	if m.Kind == KindShare {
+		if m.To != p.index {
+			return fmt.Errorf("%w: share of %d", ErrUnexpectedMessage, m.To)
+		}
+	} else if m.To != 0 {
+		return fmt.Errorf("%w: %s message should be broadcasted", ErrInvalidMessage, m.Kind)
+	}
+	switch m.Kind {
+	case KindDeal, KindCommitment:
+		commitments, err := p.decodeCommitments(m.Commitments)
+		if err != nil {
+			return err
+		}
+		store := p.pedersen
+		if m.Kind == KindCommitment {
+			store = p.feldman
+		}
+		if _, ok := store[m.From]; ok {
+			return ErrDuplicateMessage
+		}
+		store[m.From] = commitments
+	case KindShare:
+		s, err := decodeShare(m)
+		if err != nil {
+			return err
+		}
+		if _, ok := p.shares[m.From]; ok {
+			return ErrDuplicateMessage
+		}
+		p.shares[m.From] = s
+	case KindComplaint:
+		if !p.isParticipant(m.Target) || m.Target == m.From {
+			return fmt.Errorf("%w: bad target %d", ErrInvalidMessage, m.Target)
+		}
+		c := complaint{m.From, m.Target}
+		if p.complaints[c] {
+			return ErrDuplicateMessage
+		}
+		p.complaints[c] = true
+	default:
+		// justification, public complaint and reveal carry a share
+		if !p.isParticipant(m.Target) || m.Target == m.From {
+			return fmt.Errorf("%w: bad target %d", ErrInvalidMessage, m.Target)
+		}
+		s, err := decodeShare(m)
+		if err != nil {
+			return err
+		}
+		c := complaint{m.From, m.Target}
+		store := p.reveals
+		switch m.Kind {
+		case KindJustification:
+			// justification is indexed by complaint it answers
+			c = complaint{m.Target, m.From}
+			store = p.justifications
+		case KindPublicComplaint:
+			store = p.publicComplaints
+		}
+		if _, ok := store[c]; ok {
+			return ErrDuplicateMessage
+		}
+		store[c] = s
+	}
+	return nil
+}
+
+// Finalize evaluates reveals of the last round and returns the secret key
+// share and the shared public key.
+func (p *Participant) Finalize() (*Result, error) {
+	if p.round < finalRound-1 {
+		return nil, ErrNotFinished
+	}
+	p.round = finalRound
+	x := new(bls.Fr)
+	publicKey := p.g.Zero()
+	for _, dealer := range p.qualified {
+		x.Add(x, p.shareOf(dealer).value)
+		if !p.reconstruct[dealer] {
+			p.g.Add(publicKey, publicKey, p.feldman[dealer][0])
+			continue
+		}
+		secret, err := p.reconstructSecret(dealer)
+		if err != nil {
+			return nil, err
+		}
+		p.g.Add(publicKey, publicKey, p.g.MulScalar(&bls.PointG1{}, &bls.G1One, secret.ToBig()))
+	}
+	p.g.Affine(publicKey)
+	qualified := make([]uint64, len(p.qualified))
+	copy(qualified, p.qualified)
+	return &Result{
+		Index:     p.index,
+		Share:     x,
+		PublicKey: publicKey,
+		Qualified: qualified,
+	}, nil
+}
+
+// deal samples a random secret and its blinding, and returns Pedersen
+// commitments to the polynomials and shares of other participants.
+func (p *Participant) deal() ([]*Message, error) {
+	p.a = make([]*bls.Fr, p.t)
+	p.b = make([]*bls.Fr, p.t)
+	for i := 0; i < p.t; i++ {
+		var err error
+		if p.a[i], err = new(bls.Fr).Rand(p.r); err != nil {
+			return nil, err
+		}
+		if p.b[i], err = new(bls.Fr).Rand(p.r); err != nil {
+			return nil, err
+		}
+	}
+	h := bls.PedersenGenerator()
+	commitments := make([]*bls.PointG1, p.t)
+	for i := 0; i < p.t; i++ {
+		c, err := p.g.MultiExp(&bls.PointG1{}, []*bls.PointG1{&bls.G1One, h}, []*big.Int{p.a[i].ToBig(), p.b[i].ToBig()})
+		if err != nil {
+			return nil, err
+		}
+		commitments[i] = c
+	}
+	p.pedersen[p.index] = commitments
+	msgs := []*Message{{Kind: KindDeal, From: p.index, Commitments: p.encodeCommitments(commitments)}}
+	for j := uint64(1); j <= uint64(p.n); j++ {
+		x := new(bls.Fr).SetUint64(j)
+		s := &share{bls.EvalPolynomial(p.a, x), bls.EvalPolynomial(p.b, x)}
+		if j == p.index {
+			p.shares[j] = s
+			continue
+		}
+		msgs = append(msgs, &Message{Kind: KindShare, From: p.index, To: j, Share: s.value.ToBytes(), Blinding: s.blinding.ToBytes()})
+	}
+	return msgs, nil
+}
+
+// complain returns complaints against dealers whose share is missing or does
+// not match their Pedersen commitments.
+func (p *Participant) complain() []*Message {
+	var msgs []*Message
+	for dealer := uint64(1); dealer <= uint64(p.n); dealer++ {
+		if dealer == p.index {
+			continue
+		}
+		s, ok := p.shares[dealer]
+		commitments, ok2 := p.pedersen[dealer]
+		if ok && ok2 && bls.PedersenVerify(s.value, s.blinding, p.index, commitments) {
+			continue
+		}
+		p.complaints[complaint{p.index, dealer}] = true
+		msgs = append(msgs, &Message{Kind: KindComplaint, From: p.index, Target: dealer})
+	}
+	return msgs
+}
+
+// justify reveals shares of participants complaining against this dealer.
+func (p *Participant) justify() []*Message {
+	var msgs []*Message
+	for j := uint64(1); j <= uint64(p.n); j++ {
+		if !p.complaints[complaint{j, p.index}] {
+			continue
+		}
+		x := new(bls.Fr).SetUint64(j)
+		s := &share{bls.EvalPolynomial(p.a, x), bls.EvalPolynomial(p.b, x)}
+		p.justifications[complaint{j, p.index}] = s
+		msgs = append(msgs, &Message{Kind: KindJustification, From: p.index, Target: j, Share: s.value.ToBytes(), Blinding: s.blinding.ToBytes()})
+	}
+	return msgs
+}
+
+// qualify disqualifies dealers without commitments or with a complaint that
+// is not justified by a share matching their commitments. Complaining
+// participants adopt justified shares.
+func (p *Participant) qualify() {
+	p.qualified = p.qualified[:0]
+	for dealer := uint64(1); dealer <= uint64(p.n); dealer++ {
+		commitments, ok := p.pedersen[dealer]
+		if !ok {
+			continue
+		}
+		qualified := true
+		for j := uint64(1); j <= uint64(p.n) && qualified; j++ {
+			c := complaint{j, dealer}
+			if !p.complaints[c] {
+				continue
+			}
+			s, ok := p.justifications[c]
+			if !ok || !bls.PedersenVerify(s.value, s.blinding, j, commitments) {
+				qualified = false
+				continue
+			}
+			if j == p.index {
+				p.shares[dealer] = s
+			}
+		}
+		if qualified {
+			p.qualified = append(p.qualified, dealer)
+		}
+	}
+}
+
+// commit returns Feldman commitments to the secret polynomial if this dealer
+// is qualified.
+func (p *Participant) commit() []*Message {
+	if !p.isQualified(p.index) {
+		return nil
+	}
+	commitments := make([]*bls.PointG1, p.t)
+	for i := range p.a {
+		commitments[i] = p.g.MulScalar(&bls.PointG1{}, &bls.G1One, p.a[i].ToBig())
+	}
+	p.feldman[p.index] = commitments
+	return []*Message{{Kind: KindCommitment, From: p.index, Commitments: p.encodeCommitments(commitments)}}
+}
+
+// complainPublicly returns shares of qualified dealers that do not match
+// their Feldman commitments.
+func (p *Participant) complainPublicly() []*Message {
+	var msgs []*Message
+	for _, dealer := range p.qualified {
+		if dealer == p.index {
+			continue
+		}
+		s := p.shareOf(dealer)
+		commitments, ok := p.feldman[dealer]
+		if ok && bls.FeldmanVerify(s.value, p.index, commitments) {
+			continue
+		}
+		p.publicComplaints[complaint{p.index, dealer}] = s
+		msgs = append(msgs, &Message{Kind: KindPublicComplaint, From: p.index, Target: dealer, Share: s.value.ToBytes(), Blinding: s.blinding.ToBytes()})
+	}
+	return msgs
+}
+
+// evaluatePublicComplaints marks qualified dealers that are missing Feldman
+// commitments or have a valid public complaint for reconstruction. A public
+// complaint is valid if revealed share matches Pedersen commitments but not
+// Feldman commitments of the dealer.
+func (p *Participant) evaluatePublicComplaints() {
+	for _, dealer := range p.qualified {
+		feldman, ok := p.feldman[dealer]
+		if !ok {
+			p.reconstruct[dealer] = true
+			continue
+		}
+		for j := uint64(1); j <= uint64(p.n); j++ {
+			s, ok := p.publicComplaints[complaint{j, dealer}]
+			if !ok {
+				continue
+			}
+			if bls.PedersenVerify(s.value, s.blinding, j, p.pedersen[dealer]) && !bls.FeldmanVerify(s.value, j, feldman) {
+				p.reconstruct[dealer] = true
+				break
+			}
+		}
+	}
+}
+
+// reveal returns shares of dealers that are to be reconstructed.
+func (p *Participant) reveal() []*Message {
+	var msgs []*Message
+	for _, dealer := range p.qualified {
+		if !p.reconstruct[dealer] || dealer == p.index {
+			continue
+		}
+		s := p.shareOf(dealer)
+		p.reveals[complaint{p.index, dealer}] = s
+		msgs = append(msgs, &Message{Kind: KindReveal, From: p.index, Target: dealer, Share: s.value.ToBytes(), Blinding: s.blinding.ToBytes()})
+	}
+	return msgs
+}
+
+// reconstructSecret interpolates secret of a dealer from revealed shares that
+// match its Pedersen commitments.
+func (p *Participant) reconstructSecret(dealer uint64) (*bls.Fr, error) {
+	shares := make([]*bls.Fr, 0, p.t)
+	indexes := make([]uint64, 0, p.t)
+	for j := uint64(1); j <= uint64(p.n) && len(shares) < p.t; j++ {
+		s, ok := p.reveals[complaint{j, dealer}]
+		if !ok || !bls.PedersenVerify(s.value, s.blinding, j, p.pedersen[dealer]) {
+			continue
+		}
+		shares = append(shares, s.value)
+		indexes = append(indexes, j)
+	}
+	if len(shares) < p.t {
+		return nil, fmt.Errorf("not enough valid shares to reconstruct secret of dealer %d", dealer)
+	}
+	return bls.RecoverSecret(shares, indexes)
+}
+
+// shareOf returns share received from a qualified dealer. A qualified dealer
+// either sent a valid share or justified it.
+func (p *Participant) shareOf(dealer uint64) *share {
+	return p.shares[dealer]
+}
+
+func (p *Participant) isParticipant(index uint64) bool {
+	return index >= 1 && index <= uint64(p.n)
+}
+
+func (p *Participant) isQualified(index uint64) bool {
+	for _, dealer := range p.qualified {
+		if dealer == index {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Participant) encodeCommitments(commitments []*bls.PointG1) [][]byte {
+	out := make([][]byte, len(commitments))
+	for i := range commitments {
+		out[i] = p.g.ToCompressed(commitments[i])
+	}
+	return out
+}
+
+func (p *Participant) decodeCommitments(in [][]byte) ([]*bls.PointG1, error) {
+	if len(in) != p.t {
+		return nil, fmt.Errorf("%w: expected %d commitments, got %d", ErrInvalidMessage, p.t, len(in))
+	}
+	commitments := make([]*bls.PointG1, len(in))
+	for i := range in {
+		c, err := p.g.FromCompressedStrict(in[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: commitment %d: %v", ErrInvalidMessage, i, err)
+		}
+		commitments[i] = c
+	}
+	return commitments, nil
+}
+
+func decodeShare(m *Message) (*share, error) {
+	value, err := new(bls.Fr).FromBytesCanonical(m.Share)
+	if err != nil {
+		return nil, fmt.Errorf("%w: share: %v", ErrInvalidMessage, err)
+	}
+	blinding, err := new(bls.Fr).FromBytesCanonical(m.Blinding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: blinding: %v", ErrInvalidMessage, err)
+	}
+	return &share{value, blinding}, nil
+}
@@ -0,0 +1,193 @@
+package dkg
+
+import (
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// tamperTransport lets a test modify or drop messages. Returning nil from
+// tamper drops the message.
+type tamperTransport struct {
+	Transport
+	tamper func(m *Message) *Message
+}
+
+func (tr *tamperTransport) Send(m *Message) error {
+	if m = tr.tamper(copyMessage(m)); m == nil {
+		return nil
+	}
+	return tr.Transport.Send(m)
+}
+
+func run(t *testing.T, tt, nn int, tamper func(m *Message) *Message) []*Result {
+	participants := make([]*Participant, nn)
+	for i := range participants {
+		p, err := NewParticipant(uint64(i+1), tt, nn, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		participants[i] = p
+	}
+	var tr Transport = NewMemoryTransport(nn)
+	if tamper != nil {
+		tr = &tamperTransport{tr, tamper}
+	}
+	results, err := Run(participants, tr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+// checkResults checks that all honest participants agree on qualified dealers
+// and public key, and that any t shares recover the secret key of public key.
+// Result of a dishonest dealer is not checked since its view differs.
+func checkResults(t *testing.T, tt int, results []*Result, qualified []uint64, dishonest uint64) {
+	t.Helper()
+	if dishonest != 0 {
+		results = append(append([]*Result{}, results[:dishonest-1]...), results[dishonest:]...)
+	}
+	g := bls.NewG1(bls.NewFp())
+	for _, r := range results {
+		if !reflect.DeepEqual(r.Qualified, qualified) {
+			t.Fatalf("participant %d: expected qualified %v, got %v", r.Index, qualified, r.Qualified)
+		}
+		if !g.Equal(r.PublicKey, results[0].PublicKey) {
+			t.Fatalf("participant %d: public key mismatch", r.Index)
+		}
+	}
+	for _, subset := range [][]*Result{results[:tt], results[len(results)-tt:]} {
+		shares := make([]*bls.Fr, tt)
+		indexes := make([]uint64, tt)
+		for i, r := range subset {
+			shares[i], indexes[i] = r.Share, r.Index
+		}
+		secret, err := bls.RecoverSecret(shares, indexes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(g.MulScalar(&bls.PointG1{}, &bls.G1One, secret.ToBig()), results[0].PublicKey) {
+			t.Fatalf("recovered secret does not match public key")
+		}
+	}
+}
+
+func TestDKG(t *testing.T) {
+	tt, nn := 3, 5
+	t.Run("Honest", func(t *testing.T) {
+		results := run(t, tt, nn, nil)
+		checkResults(t, tt, results, []uint64{1, 2, 3, 4, 5}, 0)
+	})
+	t.Run("JustifiedComplaint", func(t *testing.T) {
+		// dealer 2 sends a bad share to 3 but justifies it
+		results := run(t, tt, nn, func(m *Message) *Message {
+			if m.Kind == KindShare && m.From == 2 && m.To == 3 {
+				m.Share = new(bls.Fr).One().ToBytes()
+			}
+			return m
+		})
+		checkResults(t, tt, results, []uint64{1, 2, 3, 4, 5}, 0)
+	})
+	t.Run("UnjustifiedComplaint", func(t *testing.T) {
+		// dealer 2 does not send a share to 3 and does not justify it
+		results := run(t, tt, nn, func(m *Message) *Message {
+			if (m.Kind == KindShare && m.From == 2 && m.To == 3) || (m.Kind == KindJustification && m.From == 2) {
+				return nil
+			}
+			return m
+		})
+		checkResults(t, tt, results, []uint64{1, 3, 4, 5}, 2)
+	})
+	t.Run("MissingDeal", func(t *testing.T) {
+		results := run(t, tt, nn, func(m *Message) *Message {
+			if m.From == 5 && m.Kind <= KindJustification {
+				return nil
+			}
+			return m
+		})
+		checkResults(t, tt, results, []uint64{1, 2, 3, 4}, 5)
+	})
+	t.Run("Reconstruction", func(t *testing.T) {
+		// dealer 4 broadcasts Feldman commitments of another polynomial so
+		// its secret is reconstructed
+		g := bls.NewG1(bls.NewFp())
+		results := run(t, tt, nn, func(m *Message) *Message {
+			if m.Kind == KindCommitment && m.From == 4 {
+				m.Commitments[1] = g.ToCompressed(&bls.G1One)
+			}
+			return m
+		})
+		checkResults(t, tt, results, []uint64{1, 2, 3, 4, 5}, 4)
+	})
+	t.Run("MissingCommitments", func(t *testing.T) {
+		results := run(t, tt, nn, func(m *Message) *Message {
+			if m.Kind == KindCommitment && m.From == 1 {
+				return nil
+			}
+			return m
+		})
+		checkResults(t, tt, results, []uint64{1, 2, 3, 4, 5}, 0)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	tt, nn := 2, 3
+	p1, _ := NewParticipant(1, tt, nn, rand.Reader)
+	p2, _ := NewParticipant(2, tt, nn, rand.Reader)
+	msgs, err := p1.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p2.Next(); err != nil {
+		t.Fatal(err)
+	}
+	var deal, share *Message
+	for _, m := range msgs {
+		if m.Kind == KindDeal {
+			deal = m
+		} else if m.To == 2 {
+			share = m
+		}
+	}
+	for _, c := range []struct {
+		m   *Message
+		err error
+	}{
+		{&Message{Kind: KindReveal + 1, From: 1}, ErrInvalidMessage},
+		{&Message{Kind: KindComplaint, From: 4, Target: 1}, ErrInvalidMessage},
+		{&Message{Kind: KindComplaint, From: 2, Target: 1}, ErrInvalidMessage},
+		{&Message{Kind: KindComplaint, From: 1, Target: 1}, ErrInvalidMessage},
+		{&Message{Kind: KindDeal, From: 1, Commitments: deal.Commitments[:1]}, ErrInvalidMessage},
+		{&Message{Kind: KindDeal, From: 1, Commitments: [][]byte{deal.Commitments[0], make([]byte, 48)}}, ErrInvalidMessage},
+		{&Message{Kind: KindShare, From: 1, To: 3, Share: share.Share, Blinding: share.Blinding}, ErrUnexpectedMessage},
+		{&Message{Kind: KindShare, From: 1, To: 2, Share: share.Share}, ErrInvalidMessage},
+		{deal, nil},
+		{deal, ErrDuplicateMessage},
+		{share, nil},
+		{share, ErrDuplicateMessage},
+	} {
+		if err := p2.Process(c.m); !errors.Is(err, c.err) {
+			t.Fatalf("expected %v, got %v", c.err, err)
+		}
+	}
+	if _, err := p2.Finalize(); !errors.Is(err, ErrNotFinished) {
+		t.Fatalf("expected %v, got %v", ErrNotFinished, err)
+	}
+	if _, err := p2.Next(); err != nil {
+		t.Fatal(err)
+	}
+	// deal round is closed
+	if err := p2.Process(&Message{Kind: KindDeal, From: 3, Commitments: deal.Commitments}); !errors.Is(err, ErrUnexpectedMessage) {
+		t.Fatalf("expected %v, got %v", ErrUnexpectedMessage, err)
+	}
+	if _, err := NewParticipant(0, tt, nn, nil); err == nil {
+		t.Fatalf("zero index is accepted")
+	}
+	if _, err := NewParticipant(1, nn+1, nn, nil); err == nil {
+		t.Fatalf("threshold larger than number of participants is accepted")
+	}
+}
@@ -0,0 +1,112 @@
+package dkg
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Transport delivers protocol messages between participants.
+type Transport interface {
+	// Send delivers a share message to its recipient and a broadcast message
+	// to all participants except the sender.
+	Send(m *Message) error
+	// Receive returns messages delivered to participant with given index
+	// since the last call.
+	Receive(index uint64) ([]*Message, error)
+}
+
+// MemoryTransport is an in process Transport for n participants.
+type MemoryTransport struct {
+	mu     sync.Mutex
+	n      int
+	queues map[uint64][]*Message
+}
+
+// NewMemoryTransport creates transport for participants with indexes in
+// range [1, n].
+func NewMemoryTransport(n int) *MemoryTransport {
+	return &MemoryTransport{n: n, queues: make(map[uint64][]*Message)}
+}
+
+func (tr *MemoryTransport) Send(m *Message) error {
+	if m.From < 1 || m.From > uint64(tr.n) {
+		return fmt.Errorf("unknown sender %d", m.From)
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if m.To != 0 {
+		if m.To > uint64(tr.n) {
+			return fmt.Errorf("unknown recipient %d", m.To)
+		}
+		tr.queues[m.To] = append(tr.queues[m.To], copyMessage(m))
+		return nil
+	}
+	for i := uint64(1); i <= uint64(tr.n); i++ {
+		if i != m.From {
+			tr.queues[i] = append(tr.queues[i], copyMessage(m))
+		}
+	}
+	return nil
+}
+
+func (tr *MemoryTransport) Receive(index uint64) ([]*Message, error) {
+	if index < 1 || index > uint64(tr.n) {
+		return nil, fmt.Errorf("unknown recipient %d", index)
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	msgs := tr.queues[index]
+	delete(tr.queues, index)
+	return msgs, nil
+}
+
+// Run runs protocol round by round for participants that share the
+// transport and returns their results. Errors of processing messages are
+// passed to onError if it is not nil, since a misbehaving participant should
+// not stop the others.
+func Run(participants []*Participant, tr Transport, onError func(index uint64, m *Message, err error)) ([]*Result, error) {
+	for round := 1; round < finalRound; round++ {
+		for _, p := range participants {
+			msgs, err := p.Next()
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range msgs {
+				if err := tr.Send(m); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, p := range participants {
+			msgs, err := tr.Receive(p.Index())
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range msgs {
+				if err := p.Process(m); err != nil && onError != nil {
+					onError(p.Index(), m, err)
+				}
+			}
+		}
+	}
+	results := make([]*Result, len(participants))
+	for i, p := range participants {
+		r, err := p.Finalize()
+		if err != nil {
+			return nil, err
+		}
+		results[i] = r
+	}
+	return results, nil
+}
+
+func copyMessage(m *Message) *Message {
+	c := *m
+	c.Commitments = make([][]byte, len(m.Commitments))
+	for i := range m.Commitments {
+		c.Commitments[i] = append([]byte{}, m.Commitments[i]...)
+	}
+	c.Share = append([]byte{}, m.Share...)
+	c.Blinding = append([]byte{}, m.Blinding...)
+	return &c
+}
@@ -0,0 +1,115 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+var keygenSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+
+// DeriveMasterSK derives master secret key from a seed of at least 32 bytes.
+// https://eips.ethereum.org/EIPS/eip-2333
+func DeriveMasterSK(seed []byte) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, fmt.Errorf("%w: seed should be at least 32 bytes", ErrInvalidLength)
+	}
+	return hkdfModR(seed)
+}
+
+// DeriveChildSK derives secret key of child at given index from parent
+// secret key.
+// https://eips.ethereum.org/EIPS/eip-2333
+func DeriveChildSK(parentSK *big.Int, index uint32) (*big.Int, error) {
+	if parentSK.Sign() < 0 || parentSK.Cmp(q) >= 0 {
+		return nil, errors.New("parent secret key should be less than curve order")
+	}
+	return hkdfModR(parentSKToLamportPK(parentSK, index))
+}
+
+// DeriveSKFromPath derives secret key from a seed along an EIP-2334 path such
+// as "m/12381/3600/0/0/0".
+func DeriveSKFromPath(seed []byte, path string) (*big.Int, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if sk, err = DeriveChildSK(sk, index); err != nil {
+			return nil, err
+		}
+	}
+	return sk, nil
+}
+
+// ParsePath parses an EIP-2334 path and returns child indexes following the
+// master node "m".
+// https://eips.ethereum.org/EIPS/eip-2334
+func ParsePath(path string) ([]uint32, error) {
+	nodes := strings.Split(path, "/")
+	if nodes[0] != "m" {
+		return nil, fmt.Errorf("path should start with master node m")
+	}
+	indexes := make([]uint32, len(nodes)-1)
+	for i, node := range nodes[1:] {
+		index, err := strconv.ParseUint(node, 10, 32)
+		if err != nil || node != strconv.FormatUint(index, 10) {
+			return nil, fmt.Errorf("invalid index %q at path component %d", node, i+1)
+		}
+		indexes[i] = uint32(index)
+	}
+	return indexes, nil
+}
+
+func hkdfModR(ikm []byte) (*big.Int, error) {
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = 48
+	salt := keygenSalt
+	ikm = append(append([]byte{}, ikm...), 0)
+	okm := make([]byte, L)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		r := hkdf.New(sha256.New, ikm, salt, []byte{0, L})
+		if _, err := io.ReadFull(r, okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm)
+		sk.Mod(sk, q)
+	}
+	return sk, nil
+}
+
+func parentSKToLamportPK(parentSK *big.Int, index uint32) []byte {
+	salt := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+	ikm := make([]byte, 32)
+	b := parentSK.Bytes()
+	copy(ikm[32-len(b):], b)
+	notIKM := make([]byte, 32)
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+	h := sha256.New()
+	for _, in := range [][]byte{ikm, notIKM} {
+		// lamport secret key is 255 chunks of 32 bytes
+		r := hkdf.New(sha256.New, in, salt, nil)
+		chunk := make([]byte, 32)
+		for i := 0; i < 255; i++ {
+			io.ReadFull(r, chunk)
+			pk := sha256.Sum256(chunk)
+			h.Write(pk[:])
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,99 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestEIP2333(t *testing.T) {
+	bigFromDec := func(s string) *big.Int {
+		a, _ := new(big.Int).SetString(s, 10)
+		return a
+	}
+	// https://eips.ethereum.org/EIPS/eip-2333#test-cases
+	vectors := []struct {
+		seed     string
+		masterSK string
+		index    uint32
+		childSK  string
+	}{
+		{
+			"0xc55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			"6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			0,
+			"20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			"0x3141592653589793238462643383279502884197169399375105820974944592",
+			"29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			3141592653,
+			"25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			"0x0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+			"27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			4294967295,
+			"29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+		{
+			"0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			"19022158461524446591288038168518313374041767046816487870552872741050760015818",
+			42,
+			"31372231650479070279774297061823572166496564838472787488249775572789064611981",
+		},
+	}
+	for _, v := range vectors {
+		seed := bytes_(-1, v.seed)
+		masterSK, err := DeriveMasterSK(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if masterSK.Cmp(bigFromDec(v.masterSK)) != 0 {
+			t.Fatalf("bad master key")
+		}
+		childSK, err := DeriveChildSK(masterSK, v.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if childSK.Cmp(bigFromDec(v.childSK)) != 0 {
+			t.Fatalf("bad child key")
+		}
+		sk, err := DeriveSKFromPath(seed, "m/"+big.NewInt(int64(v.index)).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sk.Cmp(childSK) != 0 {
+			t.Fatalf("bad key from path")
+		}
+	}
+	if _, err := DeriveMasterSK(make([]byte, 31)); err == nil {
+		t.Fatalf("short seed should be rejected")
+	}
+	if _, err := DeriveChildSK(q, 0); err == nil {
+		t.Fatalf("parent key should be reduced")
+	}
+}
+
+func TestEIP2334Path(t *testing.T) {
+	indexes, err := ParsePath("m/12381/3600/0/0/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{12381, 3600, 0, 0, 0}
+	if len(indexes) != len(expected) {
+		t.Fatalf("bad path length")
+	}
+	for i := range expected {
+		if indexes[i] != expected[i] {
+			t.Fatalf("bad index at %d", i)
+		}
+	}
+	if indexes, err := ParsePath("m"); err != nil || len(indexes) != 0 {
+		t.Fatalf("master path should be parsed")
+	}
+	for _, path := range []string{"", "1/2", "m/", "m//0", "m/m/0", "m/-1", "m/+1", "m/01", "m/4294967296", "m/12381/bad"} {
+		if _, err := ParsePath(path); err == nil {
+			t.Fatalf("path %q should be rejected", path)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+// Package eip2537 implements BLS12-381 precompiled contracts of EIP-2537.
+package eip2537
+
+import (
+	"errors"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Errors returned by precompiles. Error messages follow reference
+// implementation so that callers can report them as is.
+var (
+	ErrInvalidInputLength          = errors.New("invalid input length")
+	ErrInvalidFieldElementTopBytes = errors.New("invalid field element top bytes")
+	ErrFieldElementNotCanonical    = errors.New("must be less than modulus")
+	ErrPointNotOnCurve             = errors.New("point is not on curve")
+	ErrG1PointSubgroup             = errors.New("g1 point is not on correct subgroup")
+	ErrG2PointSubgroup             = errors.New("g2 point is not on correct subgroup")
+)
+
+const (
+	g1AddGas          uint64 = 600
+	g1MulGas          uint64 = 12000
+	g2AddGas          uint64 = 4500
+	g2MulGas          uint64 = 55000
+	pairingBaseGas    uint64 = 115000
+	pairingPerPairGas uint64 = 23000
+	mapG1Gas          uint64 = 5500
+	mapG2Gas          uint64 = 110000
+)
+
+var msmDiscountTable = [128]uint64{1200, 888, 764, 641, 594, 547, 500, 453, 438, 423, 408, 394, 379, 364, 349, 334, 330, 326, 322, 318, 314, 310, 306, 302, 298, 294, 289, 285, 281, 277, 273, 269, 268, 266, 265, 263, 262, 260, 259, 257, 256, 254, 253, 251, 250, 248, 247, 245, 244, 242, 241, 239, 238, 236, 235, 233, 232, 231, 229, 228, 226, 225, 223, 222, 221, 220, 219, 219, 218, 217, 216, 216, 215, 214, 213, 213, 212, 211, 211, 210, 209, 208, 208, 207, 206, 205, 205, 204, 203, 202, 202, 201, 200, 199, 199, 198, 197, 196, 196, 195, 194, 193, 193, 192, 191, 191, 190, 189, 188, 188, 187, 186, 185, 185, 184, 183, 182, 182, 181, 180, 179, 179, 178, 177, 176, 176, 175, 174}
+
+const (
+	fieldElementSize = 64
+	g1PointSize      = 2 * fieldElementSize
+	g2PointSize      = 4 * fieldElementSize
+	scalarSize       = 32
+	g1MulInputSize   = g1PointSize + scalarSize
+	g2MulInputSize   = g2PointSize + scalarSize
+	pairInputSize    = g1PointSize + g2PointSize
+)
+
+// Precompile is a precompiled contract as seen by the EVM. Precompiles are
+// stateless and safe for concurrent use.
+type Precompile interface {
+	RequiredGas(input []byte) uint64
+	Run(input []byte) ([]byte, error)
+}
+
+// Precompiles maps precompiles to the last byte of their addresses.
+var Precompiles = map[byte]Precompile{
+	0x0a: &G1Add{},
+	0x0b: &G1Mul{},
+	0x0c: &G1MSM{},
+	0x0d: &G2Add{},
+	0x0e: &G2Mul{},
+	0x0f: &G2MSM{},
+	0x10: &PairingCheck{},
+	0x11: &MapFpToG1{},
+	0x12: &MapFp2ToG2{},
+}
+
+// G1Add implements G1ADD precompile.
+type G1Add struct{}
+
+func (c *G1Add) RequiredGas(input []byte) uint64 {
+	return g1AddGas
+}
+
+func (c *G1Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g1PointSize {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls.NewG1(bls.NewFp())
+	p0, err := decodeG1(g, input[:g1PointSize])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeG1(g, input[g1PointSize:])
+	if err != nil {
+		return nil, err
+	}
+	r := g.Add(g.Zero(), p0, p1)
+	return encodeG1(g, r), nil
+}
+
+// G1Mul implements G1MUL precompile.
+type G1Mul struct{}
+
+func (c *G1Mul) RequiredGas(input []byte) uint64 {
+	return g1MulGas
+}
+
+func (c *G1Mul) Run(input []byte) ([]byte, error) {
+	if len(input) != g1MulInputSize {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls.NewG1(bls.NewFp())
+	p, err := decodeG1(g, input[:g1PointSize])
+	if err != nil {
+		return nil, err
+	}
+	e := new(big.Int).SetBytes(input[g1PointSize:])
+	r := g.MulScalar(g.Zero(), p, e)
+	return encodeG1(g, r), nil
+}
+
+// G1MSM implements G1MSM precompile.
+type G1MSM struct{}
+
+func (c *G1MSM) RequiredGas(input []byte) uint64 {
+	return msmGas(len(input)/g1MulInputSize, g1MulGas)
+}
+
+func (c *G1MSM) Run(input []byte) ([]byte, error) {
+	k := len(input) / g1MulInputSize
+	if len(input) == 0 || len(input)%g1MulInputSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	g := bls.NewG1(bls.NewFp())
+	points := make([]*bls.PointG1, k)
+	scalars := make([]*big.Int, k)
+	for i := 0; i < k; i++ {
+		in := input[i*g1MulInputSize : (i+1)*g1MulInputSize]
+		p, err := decodeG1(g, in[:g1PointSize])
+		if err != nil {
+			return nil, err
+		}
+		points[i] = p
+		scalars[i] = new(big.Int).SetBytes(in[g1PointSize:])
+	}
+	r, err := g.MultiExp(g.Zero(), points, scalars)
+	if err != nil {
+		return nil, err
+	}
+	return encodeG1(g, r), nil
+}
+
+// G2Add implements G2ADD precompile.
+type G2Add struct{}
+
+func (c *G2Add) RequiredGas(input []byte) uint64 {
+	return g2AddGas
+}
+
+func (c *G2Add) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*g2PointSize {
+		return nil, ErrInvalidInputLength
+	}
+	g := newG2()
+	p0, err := decodeG2(g, input[:g2PointSize])
+	if err != nil {
+		return nil, err
+	}
+	p1, err := decodeG2(g, input[g2PointSize:])
+	if err != nil {
+		return nil, err
+	}
+	r := g.Add(g.Zero(), p0, p1)
+	return encodeG2(g, r), nil
+}
+
+// G2Mul implements G2MUL precompile.
+type G2Mul struct{}
+
+func (c *G2Mul) RequiredGas(input []byte) uint64 {
+	return g2MulGas
+}
+
+func (c *G2Mul) Run(input []byte) ([]byte, error) {
+	if len(input) != g2MulInputSize {
+		return nil, ErrInvalidInputLength
+	}
+	g := newG2()
+	p, err := decodeG2(g, input[:g2PointSize])
+	if err != nil {
+		return nil, err
+	}
+	e := new(big.Int).SetBytes(input[g2PointSize:])
+	r := g.MulScalar(g.Zero(), p, e)
+	return encodeG2(g, r), nil
+}
+
+// G2MSM implements G2MSM precompile.
+type G2MSM struct{}
+
+func (c *G2MSM) RequiredGas(input []byte) uint64 {
+	return msmGas(len(input)/g2MulInputSize, g2MulGas)
+}
+
+func (c *G2MSM) Run(input []byte) ([]byte, error) {
+	k := len(input) / g2MulInputSize
+	if len(input) == 0 || len(input)%g2MulInputSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	g := newG2()
+	points := make([]*bls.PointG2, k)
+	scalars := make([]*big.Int, k)
+	for i := 0; i < k; i++ {
+		in := input[i*g2MulInputSize : (i+1)*g2MulInputSize]
+		p, err := decodeG2(g, in[:g2PointSize])
+		if err != nil {
+			return nil, err
+		}
+		points[i] = p
+		scalars[i] = new(big.Int).SetBytes(in[g2PointSize:])
+	}
+	r, err := g.MultiExp(g.Zero(), points, scalars)
+	if err != nil {
+		return nil, err
+	}
+	return encodeG2(g, r), nil
+}
+
+// PairingCheck implements PAIRING_CHECK precompile. Output is 32 bytes
+// big endian encoding of 1 if product of pairings equals to identity and 0
+// otherwise.
+type PairingCheck struct{}
+
+func (c *PairingCheck) RequiredGas(input []byte) uint64 {
+	return pairingBaseGas + uint64(len(input)/pairInputSize)*pairingPerPairGas
+}
+
+func (c *PairingCheck) Run(input []byte) ([]byte, error) {
+	k := len(input) / pairInputSize
+	if len(input) == 0 || len(input)%pairInputSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	e := bls.NewBLSPairingEngine()
+	points := make([]bls.PointG1, 0, k)
+	twistPoints := make([]bls.PointG2, 0, k)
+	for i := 0; i < k; i++ {
+		in := input[i*pairInputSize : (i+1)*pairInputSize]
+		p1, err := decodeG1(e.G1, in[:g1PointSize])
+		if err != nil {
+			return nil, err
+		}
+		p2, err := decodeG2(e.G2, in[g1PointSize:])
+		if err != nil {
+			return nil, err
+		}
+		if !e.G1.InCorrectSubgroup(p1) {
+			return nil, ErrG1PointSubgroup
+		}
+		if !e.G2.InCorrectSubgroup(p2) {
+			return nil, ErrG2PointSubgroup
+		}
+		// pairs with a point at infinity contribute identity
+		if e.G1.IsZero(p1) || e.G2.IsZero(p2) {
+			continue
+		}
+		points = append(points, *p1)
+		twistPoints = append(twistPoints, *p2)
+	}
+	out := make([]byte, 32)
+	f := e.Fp12.Zero()
+	e.Pair(f, points, twistPoints)
+	if e.Fp12.Equal(f, e.Fp12.One()) {
+		out[31] = 1
+	}
+	return out, nil
+}
+
+// MapFpToG1 implements MAP_FP_TO_G1 precompile.
+type MapFpToG1 struct{}
+
+func (c *MapFpToG1) RequiredGas(input []byte) uint64 {
+	return mapG1Gas
+}
+
+func (c *MapFpToG1) Run(input []byte) ([]byte, error) {
+	if len(input) != fieldElementSize {
+		return nil, ErrInvalidInputLength
+	}
+	fe := &bls.Fe{}
+	if err := decodeFieldElement(bls.NewFp(), fe, input); err != nil {
+		return nil, err
+	}
+	g := bls.NewG1(bls.NewFp())
+	return encodeG1(g, g.MapToCurve(fe)), nil
+}
+
+// MapFp2ToG2 implements MAP_FP2_TO_G2 precompile.
+type MapFp2ToG2 struct{}
+
+func (c *MapFp2ToG2) RequiredGas(input []byte) uint64 {
+	return mapG2Gas
+}
+
+func (c *MapFp2ToG2) Run(input []byte) ([]byte, error) {
+	if len(input) != 2*fieldElementSize {
+		return nil, ErrInvalidInputLength
+	}
+	fe := &bls.Fe2{}
+	if err := decodeFieldElement2(bls.NewFp(), fe, input); err != nil {
+		return nil, err
+	}
+	g := newG2()
+	return encodeG2(g, g.MapToCurve(fe)), nil
+}
+
+func msmGas(k int, mulGas uint64) uint64 {
+	if k == 0 {
+		return 0
+	}
+	discount := msmDiscountTable[len(msmDiscountTable)-1]
+	if k < len(msmDiscountTable) {
+		discount = msmDiscountTable[k-1]
+	}
+	return (uint64(k) * mulGas * discount) / 1000
+}
+
+func newG2() *bls.G2 {
+	return bls.NewG2(bls.NewFp2(bls.NewFp()))
+}
+
+// decodeFieldElement decodes 64 bytes big endian field element whose top 16
+// bytes must be zero.
+func decodeFieldElement(f *bls.Fp, fe *bls.Fe, in []byte) error {
+	for i := 0; i < 16; i++ {
+		if in[i] != 0 {
+			return ErrInvalidFieldElementTopBytes
+		}
+	}
+	if err := f.NewElementFromBytes(fe, in[16:fieldElementSize]); err != nil {
+		return ErrFieldElementNotCanonical
+	}
+	return nil
+}
+
+// decodeFieldElement2 decodes 128 bytes as c0 || c1.
+func decodeFieldElement2(f *bls.Fp, fe *bls.Fe2, in []byte) error {
+	if err := decodeFieldElement(f, &fe[0], in[:fieldElementSize]); err != nil {
+		return err
+	}
+	return decodeFieldElement(f, &fe[1], in[fieldElementSize:])
+}
+
+func encodeFieldElement(f *bls.Fp, out []byte, fe *bls.Fe) {
+	copy(out[16:fieldElementSize], f.ToBytes(fe))
+}
+
+func encodeFieldElement2(f *bls.Fp, out []byte, fe *bls.Fe2) {
+	encodeFieldElement(f, out[:fieldElementSize], &fe[0])
+	encodeFieldElement(f, out[fieldElementSize:], &fe[1])
+}
+
+// decodeG1 decodes affine x || y, where all zero input is point at infinity.
+func decodeG1(g *bls.G1, in []byte) (*bls.PointG1, error) {
+	f := bls.NewFp()
+	p := &bls.PointG1{}
+	if err := decodeFieldElement(f, &p[0], in[:fieldElementSize]); err != nil {
+		return nil, err
+	}
+	if err := decodeFieldElement(f, &p[1], in[fieldElementSize:]); err != nil {
+		return nil, err
+	}
+	if f.IsZero(&p[0]) && f.IsZero(&p[1]) {
+		return g.Zero(), nil
+	}
+	f.Copy(&p[2], f.One())
+	if !g.IsOnCurve(p) {
+		return nil, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+func encodeG1(g *bls.G1, p *bls.PointG1) []byte {
+	out := make([]byte, g1PointSize)
+	if g.IsZero(p) {
+		return out
+	}
+	f := bls.NewFp()
+	a := g.Copy(&bls.PointG1{}, p)
+	g.Affine(a)
+	encodeFieldElement(f, out[:fieldElementSize], &a[0])
+	encodeFieldElement(f, out[fieldElementSize:], &a[1])
+	return out
+}
+
+func decodeG2(g *bls.G2, in []byte) (*bls.PointG2, error) {
+	f := bls.NewFp()
+	p := &bls.PointG2{}
+	if err := decodeFieldElement2(f, &p[0], in[:2*fieldElementSize]); err != nil {
+		return nil, err
+	}
+	if err := decodeFieldElement2(f, &p[1], in[2*fieldElementSize:]); err != nil {
+		return nil, err
+	}
+	if p[0] == bls.Fp2Zero && p[1] == bls.Fp2Zero {
+		return g.Zero(), nil
+	}
+	p[2] = bls.Fp2One
+	if !g.IsOnCurve(p) {
+		return nil, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+func encodeG2(g *bls.G2, p *bls.PointG2) []byte {
+	out := make([]byte, g2PointSize)
+	if g.IsZero(p) {
+		return out
+	}
+	f := bls.NewFp()
+	a := g.Copy(&bls.PointG2{}, p)
+	g.Affine(a)
+	encodeFieldElement2(f, out[:2*fieldElementSize], &a[0])
+	encodeFieldElement2(f, out[2*fieldElementSize:], &a[1])
+	return out
+}
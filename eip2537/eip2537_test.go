@@ -0,0 +1,109 @@
+package eip2537
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type vector struct {
+	Name          string
+	Input         string
+	Expected      string
+	ExpectedError string
+	Gas           uint64
+}
+
+func loadVectors(t testing.TB, file string) []vector {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func testPrecompile(t *testing.T, p Precompile, name string) {
+	for _, v := range loadVectors(t, "bls"+name+".json") {
+		t.Run(v.Name, func(t *testing.T) {
+			in, _ := hex.DecodeString(v.Input)
+			expected, _ := hex.DecodeString(v.Expected)
+			if gas := p.RequiredGas(in); gas != v.Gas {
+				t.Fatalf("gas mismatch, have %d want %d", gas, v.Gas)
+			}
+			out, err := p.Run(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, expected) {
+				t.Fatalf("output mismatch\nhave %x\nwant %x", out, expected)
+			}
+		})
+	}
+	for _, v := range loadVectors(t, "fail-bls"+name+".json") {
+		t.Run(v.Name, func(t *testing.T) {
+			in, _ := hex.DecodeString(v.Input)
+			_, err := p.Run(in)
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			if err.Error() != v.ExpectedError {
+				t.Fatalf("error mismatch, have %q want %q", err, v.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestG1Add(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0a], "G1Add")
+}
+
+func TestG1Mul(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0b], "G1Mul")
+}
+
+func TestG1MSM(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0c], "G1MultiExp")
+}
+
+func TestG2Add(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0d], "G2Add")
+}
+
+func TestG2Mul(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0e], "G2Mul")
+}
+
+func TestG2MSM(t *testing.T) {
+	testPrecompile(t, Precompiles[0x0f], "G2MultiExp")
+}
+
+func TestPairingCheck(t *testing.T) {
+	testPrecompile(t, Precompiles[0x10], "Pairing")
+}
+
+func TestMapFpToG1(t *testing.T) {
+	testPrecompile(t, Precompiles[0x11], "MapG1")
+}
+
+func TestMapFp2ToG2(t *testing.T) {
+	testPrecompile(t, Precompiles[0x12], "MapG2")
+}
+
+func BenchmarkPairingCheck(b *testing.B) {
+	v := loadVectors(b, "blsPairing.json")[0]
+	in, _ := hex.DecodeString(v.Input)
+	p := Precompiles[0x10]
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Run(in); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
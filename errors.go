@@ -0,0 +1,13 @@
+package bls
+
+import "errors"
+
+// Errors returned by decoding, mapping and multi exponentiation functions. Returned errors may wrap
+// these with additional context, so they should be compared with errors.Is.
+var (
+	ErrInvalidLength = errors.New("invalid input length")
+	ErrNonCanonical  = errors.New("non canonical encoding")
+	ErrNotOnCurve    = errors.New("point is not on curve")
+	ErrNotInSubgroup = errors.New("point is not on correct subgroup")
+	ErrInvalidScalar = errors.New("invalid scalar")
+)
@@ -0,0 +1,281 @@
+// Package fft implements radix-2 fast Fourier transform over scalar field of
+// BLS12-381 and in the exponent over G1. Multiplicative group of the field
+// has a subgroup of order 2^32, so domains of any power of two size up to
+// 2^32 are supported.
+package fft
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// MaxOrder is the largest k such that 2^k divides q - 1.
+const MaxOrder = 32
+
+// Generator is a generator of multiplicative group of the field. It is not a
+// power of two root of unity and is used as coset shift.
+const Generator = 7
+
+// ErrInvalidSize is returned when domain size is not a power of two or is
+// larger than 2^MaxOrder.
+var ErrInvalidSize = errors.New("domain size should be a power of two not larger than 2^32")
+
+// Domain is the set of n-th roots of unity with precomputed twiddle factors.
+type Domain struct {
+	// Size is the number of elements, n
+	Size int
+	// Roots is w^i for i in [0, n) where w is a primitive n-th root of unity
+	Roots []*bls.Fr
+	// RootsInv is w^-i for i in [0, n)
+	RootsInv []*bls.Fr
+	// SizeInv is 1 / n
+	SizeInv *bls.Fr
+	// CosetShift is the generator g, coset is g * w^i for i in [0, n)
+	CosetShift    *bls.Fr
+	CosetShiftInv *bls.Fr
+
+	// twiddles of transforms in the exponent as integers, derived on first
+	// use
+	g1Once sync.Once
+	g1     *g1Twiddles
+}
+
+// g1Twiddles holds first half of roots and inverse roots as integers.
+// Inverse roots of the last layer are also scaled with 1 / n.
+type g1Twiddles struct {
+	roots, rootsInv, lastRootsInv []*big.Int
+	sizeInv                       *big.Int
+}
+
+func (d *Domain) g1Twiddles() *g1Twiddles {
+	d.g1Once.Do(func() {
+		half := d.Size / 2
+		tw := &g1Twiddles{
+			roots:        make([]*big.Int, half),
+			rootsInv:     make([]*big.Int, half),
+			lastRootsInv: make([]*big.Int, half),
+			sizeInv:      d.SizeInv.ToBig(),
+		}
+		t := new(bls.Fr)
+		for j := 0; j < half; j++ {
+			tw.roots[j] = d.Roots[j].ToBig()
+			tw.rootsInv[j] = d.RootsInv[j].ToBig()
+			t.Mul(d.RootsInv[j], d.SizeInv)
+			tw.lastRootsInv[j] = t.ToBig()
+		}
+		d.g1 = tw
+	})
+	return d.g1
+}
+
+// NewDomain creates a domain of size n.
+func NewDomain(n int) (*Domain, error) {
+	if n <= 0 || n&(n-1) != 0 || uint64(n) > 1<<MaxOrder {
+		return nil, ErrInvalidSize
+	}
+	d := &Domain{
+		Size:          n,
+		Roots:         powers(rootOfUnity(n), n),
+		SizeInv:       new(bls.Fr).SetUint64(uint64(n)),
+		CosetShift:    new(bls.Fr).SetUint64(Generator),
+		CosetShiftInv: new(bls.Fr).SetUint64(Generator),
+	}
+	d.SizeInv.Inverse(d.SizeInv)
+	d.CosetShiftInv.Inverse(d.CosetShiftInv)
+	// w^-i = w^(n-i)
+	d.RootsInv = make([]*bls.Fr, n)
+	d.RootsInv[0] = new(bls.Fr).One()
+	for i := 1; i < n; i++ {
+		d.RootsInv[i] = new(bls.Fr).Set(d.Roots[n-i])
+	}
+	return d, nil
+}
+
+// rootOfUnity returns a primitive n-th root of unity, g^((q - 1) / n).
+func rootOfUnity(n int) *bls.Fr {
+	qMinusOne := new(bls.Fr)
+	qMinusOne.Neg(new(bls.Fr).One())
+	exp := new(big.Int).Div(qMinusOne.ToBig(), big.NewInt(int64(n)))
+	w := new(bls.Fr)
+	w.Exp(new(bls.Fr).SetUint64(Generator), exp)
+	return w
+}
+
+// powers returns a^i for i in [0, n).
+func powers(a *bls.Fr, n int) []*bls.Fr {
+	out := make([]*bls.Fr, n)
+	out[0] = new(bls.Fr).One()
+	for i := 1; i < n; i++ {
+		out[i] = new(bls.Fr)
+		out[i].Mul(out[i-1], a)
+	}
+	return out
+}
+
+// FFT evaluates polynomial with given coefficients at roots of unity in
+// place, values[i] = p(w^i). Input shorter than domain is an error,
+// coefficients should be padded with zeros.
+func (d *Domain) FFT(values []*bls.Fr) error {
+	if len(values) != d.Size {
+		return ErrInvalidSize
+	}
+	fft(values, d.Roots)
+	return nil
+}
+
+// InverseFFT interpolates evaluations at roots of unity in place and returns
+// coefficients.
+func (d *Domain) InverseFFT(values []*bls.Fr) error {
+	if len(values) != d.Size {
+		return ErrInvalidSize
+	}
+	fft(values, d.RootsInv)
+	for _, v := range values {
+		v.Mul(v, d.SizeInv)
+	}
+	return nil
+}
+
+// CosetFFT evaluates polynomial at the coset in place, values[i] = p(g * w^i).
+func (d *Domain) CosetFFT(values []*bls.Fr) error {
+	if len(values) != d.Size {
+		return ErrInvalidSize
+	}
+	scale(values, d.CosetShift)
+	fft(values, d.Roots)
+	return nil
+}
+
+// InverseCosetFFT interpolates evaluations at the coset in place and returns
+// coefficients.
+func (d *Domain) InverseCosetFFT(values []*bls.Fr) error {
+	if err := d.InverseFFT(values); err != nil {
+		return err
+	}
+	scale(values, d.CosetShiftInv)
+	return nil
+}
+
+// FFTG1 is FFT in the exponent, points[i] = sum_j w^(ij) * points[j].
+func (d *Domain) FFTG1(points []*bls.PointG1) error {
+	if len(points) != d.Size {
+		return ErrInvalidSize
+	}
+	tw := d.g1Twiddles()
+	fftG1(points, tw.roots, nil, nil)
+	return nil
+}
+
+// InverseFFTG1 is inverse FFT in the exponent. Applied to monomial powers of
+// tau it gives Lagrange basis of the domain evaluated at tau, L_i(tau) * G1.
+// Results are in affine form.
+func (d *Domain) InverseFFTG1(points []*bls.PointG1) error {
+	if len(points) != d.Size {
+		return ErrInvalidSize
+	}
+	tw := d.g1Twiddles()
+	fftG1(points, tw.rootsInv, tw.lastRootsInv, tw.sizeInv)
+	g := bls.NewG1(bls.NewFp())
+	for _, p := range points {
+		g.Affine(p)
+	}
+	return nil
+}
+
+// scale multiplies coefficients with powers of a, values[i] = a^i * values[i].
+func scale(values []*bls.Fr, a *bls.Fr) {
+	t := new(bls.Fr).One()
+	for _, v := range values {
+		v.Mul(v, t)
+		t.Mul(t, a)
+	}
+}
+
+// fft is iterative Cooley-Tukey transform with natural order input and
+// output. Twiddles are powers of a primitive root of unity of the input size.
+func fft(values []*bls.Fr, twiddles []*bls.Fr) {
+	n := len(values)
+	// size is checked by callers
+	_ = BitReverseFr(values)
+	t := new(bls.Fr)
+	for m := 2; m <= n; m <<= 1 {
+		half, stride := m/2, n/m
+		for k := 0; k < n; k += m {
+			for j := 0; j < half; j++ {
+				a, b := values[k+j], values[k+j+half]
+				t.Mul(b, twiddles[j*stride])
+				b.Sub(a, t)
+				a.Add(a, t)
+			}
+		}
+	}
+}
+
+// fftG1 is fft with points in place of field elements. Twiddles are the
+// first half of powers of a primitive root of unity of the input size. If
+// scale is given, outputs are multiplied with it in the last layer which
+// then uses lastTwiddles, twiddles multiplied with scale.
+func fftG1(points []*bls.PointG1, twiddles, lastTwiddles []*big.Int, scale *big.Int) {
+	n := len(points)
+	// size is checked by callers
+	_ = BitReverseG1(points)
+	g := bls.NewG1(bls.NewFp())
+	t := &bls.PointG1{}
+	for m := 2; m <= n; m <<= 1 {
+		half, stride := m/2, n/m
+		last := m == n && scale != nil
+		for k := 0; k < n; k += m {
+			for j := 0; j < half; j++ {
+				a, b := points[k+j], points[k+j+half]
+				switch {
+				case last:
+					g.MulScalar(a, a, scale)
+					g.MulScalar(t, b, lastTwiddles[j])
+				case j == 0:
+					t.Set(b)
+				default:
+					g.MulScalar(t, b, twiddles[j*stride])
+				}
+				g.Sub(b, a, t)
+				g.Add(a, a, t)
+			}
+		}
+	}
+}
+
+// BitReverseFr permutes field elements to bit reversal order of indexes.
+// Length should be a power of two.
+func BitReverseFr(s []*bls.Fr) error {
+	return bitReverse(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// BitReverseG1 permutes G1 points to bit reversal order of indexes. Length
+// should be a power of two.
+func BitReverseG1(s []*bls.PointG1) error {
+	return bitReverse(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// bitReverse swaps elements at index i and its bit reversal for a sequence
+// of length n.
+func bitReverse(n int, swap func(i, j int)) error {
+	if n <= 0 || n&(n-1) != 0 {
+		return ErrInvalidSize
+	}
+	bits := 0
+	for 1<<uint(bits) < n {
+		bits++
+	}
+	for i := 0; i < n; i++ {
+		j := 0
+		for k := 0; k < bits; k++ {
+			j |= (i >> uint(k) & 1) << uint(bits-1-k)
+		}
+		if i < j {
+			swap(i, j)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package fft
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func randFrs(t testing.TB, n int) []*bls.Fr {
+	out := make([]*bls.Fr, n)
+	for i := range out {
+		e, err := new(bls.Fr).Rand(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = e
+	}
+	return out
+}
+
+func copyFrs(in []*bls.Fr) []*bls.Fr {
+	out := make([]*bls.Fr, len(in))
+	for i := range in {
+		out[i] = new(bls.Fr).Set(in[i])
+	}
+	return out
+}
+
+// eval evaluates polynomial at x with Horner's method.
+func eval(coeffs []*bls.Fr, x *bls.Fr) *bls.Fr {
+	y := new(bls.Fr)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.Mul(y, x)
+		y.Add(y, coeffs[i])
+	}
+	return y
+}
+
+func TestRootOfUnity(t *testing.T) {
+	w := rootOfUnity(1 << MaxOrder)
+	e := new(bls.Fr)
+	e.Exp(w, big.NewInt(1<<(MaxOrder-1)))
+	if e.IsOne() {
+		t.Fatalf("root of unity is not primitive")
+	}
+	e.Square(e)
+	if !e.IsOne() {
+		t.Fatalf("bad root of unity")
+	}
+	for _, n := range []int{0, 3, 12} {
+		if _, err := NewDomain(n); err == nil {
+			t.Fatalf("domain of size %d is accepted", n)
+		}
+	}
+}
+
+func TestFFT(t *testing.T) {
+	for _, n := range []int{1, 2, 8, 64} {
+		d, err := NewDomain(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		coeffs := randFrs(t, n)
+		t.Run("Forward", func(t *testing.T) {
+			values := copyFrs(coeffs)
+			if err := d.FFT(values); err != nil {
+				t.Fatal(err)
+			}
+			for i := range values {
+				if !values[i].Equal(eval(coeffs, d.Roots[i])) {
+					t.Fatalf("bad evaluation at %d of %d", i, n)
+				}
+			}
+			if err := d.InverseFFT(values); err != nil {
+				t.Fatal(err)
+			}
+			for i := range values {
+				if !values[i].Equal(coeffs[i]) {
+					t.Fatalf("inverse does not round trip")
+				}
+			}
+		})
+		t.Run("Coset", func(t *testing.T) {
+			values := copyFrs(coeffs)
+			if err := d.CosetFFT(values); err != nil {
+				t.Fatal(err)
+			}
+			x := new(bls.Fr)
+			for i := range values {
+				x.Mul(d.CosetShift, d.Roots[i])
+				if !values[i].Equal(eval(coeffs, x)) {
+					t.Fatalf("bad evaluation at %d of %d", i, n)
+				}
+			}
+			if err := d.InverseCosetFFT(values); err != nil {
+				t.Fatal(err)
+			}
+			for i := range values {
+				if !values[i].Equal(coeffs[i]) {
+					t.Fatalf("inverse does not round trip")
+				}
+			}
+		})
+		if err := d.FFT(coeffs[1:]); err == nil {
+			t.Fatalf("input of wrong size is accepted")
+		}
+	}
+}
+
+func TestFFTG1(t *testing.T) {
+	n := 8
+	d, err := NewDomain(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := bls.NewG1(bls.NewFp())
+	// monomial powers of tau give Lagrange basis at tau
+	tau := randFrs(t, 1)[0]
+	points := make([]*bls.PointG1, n)
+	for i, e := range powers(tau, n) {
+		points[i] = g.MulScalar(&bls.PointG1{}, &bls.G1One, e.ToBig())
+	}
+	if err := d.InverseFFTG1(points); err != nil {
+		t.Fatal(err)
+	}
+	// L_i(tau) = w^i * (tau^n - 1) / (n * (tau - w^i))
+	zTau, l := new(bls.Fr), new(bls.Fr)
+	zTau.Exp(tau, big.NewInt(int64(n)))
+	zTau.Sub(zTau, new(bls.Fr).One())
+	for i := range points {
+		l.Sub(tau, d.Roots[i])
+		l.Inverse(l)
+		l.Mul(l, d.Roots[i])
+		l.Mul(l, zTau)
+		l.Mul(l, d.SizeInv)
+		if !g.Equal(points[i], g.MulScalar(&bls.PointG1{}, &bls.G1One, l.ToBig())) {
+			t.Fatalf("bad Lagrange basis point at %d", i)
+		}
+	}
+	if err := d.FFTG1(points); err != nil {
+		t.Fatal(err)
+	}
+	for i, e := range powers(tau, n) {
+		if !g.Equal(points[i], g.MulScalar(&bls.PointG1{}, &bls.G1One, e.ToBig())) {
+			t.Fatalf("inverse does not round trip")
+		}
+	}
+}
+
+func TestBitReverse(t *testing.T) {
+	d, err := NewDomain(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	brp := copyFrs(d.Roots)
+	if err := BitReverseFr(brp); err != nil {
+		t.Fatal(err)
+	}
+	for i, j := range []int{0, 4, 2, 6, 1, 5, 3, 7} {
+		if !brp[i].Equal(d.Roots[j]) {
+			t.Fatalf("bad permutation at %d", i)
+		}
+	}
+	points := []*bls.PointG1{&bls.G1One, new(bls.PointG1)}
+	if err := BitReverseG1(points); err != nil || points[0] != &bls.G1One {
+		t.Fatalf("bad permutation of two points")
+	}
+	for _, n := range []int{0, 3, 5, 6} {
+		if err := BitReverseFr(make([]*bls.Fr, n)); err != ErrInvalidSize {
+			t.Fatalf("length %d should be rejected", n)
+		}
+	}
+	if err := BitReverseG1(make([]*bls.PointG1, 5)); err != ErrInvalidSize {
+		t.Fatalf("length 5 should be rejected")
+	}
+}
+
+func BenchmarkFFT(b *testing.B) {
+	d, err := NewDomain(4096)
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := randFrs(b, 4096)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.FFT(values)
+	}
+}
@@ -67,7 +67,7 @@ func (fe *Fe) SetUint(a uint64) *Fe {
 }
 
 func (fe *Fe) SetString(s string) (*Fe, error) {
-	if s[:2] == "0x" {
+	if len(s) >= 2 && s[:2] == "0x" {
 		s = s[2:]
 	}
 	bytes, err := hex.DecodeString(s)
@@ -154,6 +154,15 @@ func (fe *Fe) Equals(fe2 *Fe) bool {
 	return fe2[0] == fe[0] && fe2[1] == fe[1] && fe2[2] == fe[2] && fe2[3] == fe[3] && fe2[4] == fe[4] && fe2[5] == fe[5]
 }
 
+// equalCT returns 1 if elements are equal and 0 otherwise in constant time.
+func (fe *Fe) equalCT(fe2 *Fe) uint64 {
+	var acc uint64
+	for i := 0; i < 6; i++ {
+		acc |= fe[i] ^ fe2[i]
+	}
+	return 1 ^ ((acc | -acc) >> 63)
+}
+
 func (fe *Fe) div2(e uint64) {
 	fe[0] = fe[0]>>1 | fe[1]<<63
 	fe[1] = fe[1]>>1 | fe[2]<<63
@@ -20,7 +20,7 @@ func NewFp() *Fp {
 func (f *Fp) NewElementFromBytes(fe *Fe, in []byte) error {
 	fe.FromBytes(in)
 	if !f.Valid(fe) {
-		return fmt.Errorf("invalid input string")
+		return fmt.Errorf("%w: field element is not less than modulus", ErrNonCanonical)
 	}
 	f.Mul(fe, fe, r2)
 	return nil
@@ -32,7 +32,7 @@ func (f *Fp) NewElementFromUint(in uint64) (*Fe, error) {
 		return fe, nil
 	}
 	if !f.Valid(fe) {
-		return nil, fmt.Errorf("invalid input string")
+		return nil, fmt.Errorf("%w: field element is not less than modulus", ErrNonCanonical)
 	}
 	f.Mul(fe, fe, r2)
 	return fe, nil
@@ -41,7 +41,7 @@ func (f *Fp) NewElementFromUint(in uint64) (*Fe, error) {
 func (f *Fp) NewElementFromBig(in *big.Int) (*Fe, error) {
 	fe := new(Fe).SetBig(in)
 	if !f.Valid(fe) {
-		return nil, fmt.Errorf("invalid input string")
+		return nil, fmt.Errorf("%w: field element is not less than modulus", ErrNonCanonical)
 	}
 	f.Mul(fe, fe, r2)
 	return fe, nil
@@ -53,7 +53,7 @@ func (f *Fp) NewElementFromString(in string) (*Fe, error) {
 		return nil, err
 	}
 	if !f.Valid(fe) {
-		return nil, fmt.Errorf("invalid input string")
+		return nil, fmt.Errorf("%w: field element is not less than modulus", ErrNonCanonical)
 	}
 	f.Mul(fe, fe, r2)
 	return fe, nil
@@ -193,7 +193,7 @@ func (f *Fp) InvMontUp(inv, fe *Fe) {
 		}
 		k += 1
 	}
-	if found && k > 384 {
+	if found && k >= 381 {
 		if r.Cmp(&modulus) != -1 || z > 0 {
 			subn(r, &modulus)
 		}
@@ -304,10 +304,154 @@ func (f *Fp) InvEEA(inv, fe *Fe) {
 	inv.Set(x2)
 }
 
+// LexicographicallyLargest returns true if a is larger than its negation,
+// that is, a > (p-1)/2 in normal form. It is the sign flag of compressed
+// point encoding.
+func (f *Fp) LexicographicallyLargest(a *Fe) bool {
+	t := new(Fe)
+	f.Demont(t, a)
+	return t.Cmp(halfModulus) > 0
+}
+
+// Sgn0 is the sgn0 function of RFC 9380, parity of a in normal form.
+func (f *Fp) Sgn0(a *Fe) bool {
+	t := new(Fe)
+	f.Demont(t, a)
+	return t.IsOdd()
+}
+
+// Sqrt sets c to a^((p+1)/4) and reports whether it is a square root of a.
 func (f *Fp) Sqrt(c, a *Fe) (hasRoot bool) {
-	var u, v Fe
-	f.Copy(&u, a)
-	f.Exp(c, a, pPlus1Over4)
-	f.Square(&v, c)
-	return f.Equal(&u, &v)
+	u, v := new(Fe).Set(a), new(Fe)
+	f.sqrtAddchain(c, a)
+	montmul(c, c, u)
+	montsquare(v, c)
+	return u.Equals(v)
+}
+
+// SqrtCT is the constant time variant of Sqrt. Candidate root is written
+// to c whether or not a is a quadratic residue.
+func (f *Fp) SqrtCT(c, a *Fe) (hasRoot bool) {
+	u, v := new(Fe).Set(a), new(Fe)
+	f.sqrtAddchain(c, a)
+	montmul(c, c, u)
+	montsquare(v, c)
+	return u.equalCT(v) == 1
+}
+
+// Legendre returns 1 if a is a non zero square, -1 if a is a non square and
+// 0 if a is zero.
+func (f *Fp) Legendre(a *Fe) int {
+	c := new(Fe)
+	// a ^ ((p-1) / 2) = (a ^ ((p-3) / 4)) ^ 2 * a
+	f.sqrtAddchain(c, a)
+	montsquare(c, c)
+	montmul(c, c, a)
+	if c.Equals(r1) {
+		return 1
+	}
+	if c.IsZero() {
+		return 0
+	}
+	return -1
+}
+
+func (f *Fp) IsSquare(a *Fe) bool {
+	return f.Legendre(a) != -1
+}
+
+// sqrtAddchain sets c to a^((p-3)/4) with a fixed addition chain.
+func (f *Fp) sqrtAddchain(c, a *Fe) {
+	chain := func(c *Fe, n int, a *Fe) {
+		for i := 0; i < n; i++ {
+			montsquare(c, c)
+		}
+		montmul(c, c, a)
+	}
+	t := [16]Fe{}
+	t[13].Set(a)
+	montsquare(&t[0], &t[13])
+	montmul(&t[8], &t[0], &t[13])
+	montsquare(&t[4], &t[0])
+	montmul(&t[1], &t[8], &t[0])
+	montmul(&t[6], &t[4], &t[8])
+	montmul(&t[9], &t[1], &t[4])
+	montmul(&t[12], &t[6], &t[4])
+	montmul(&t[3], &t[9], &t[4])
+	montmul(&t[7], &t[12], &t[4])
+	montmul(&t[15], &t[3], &t[4])
+	montmul(&t[10], &t[7], &t[4])
+	montmul(&t[2], &t[15], &t[4])
+	montmul(&t[11], &t[10], &t[4])
+	montsquare(&t[0], &t[3])
+	montmul(&t[14], &t[11], &t[4])
+	montmul(&t[5], &t[0], &t[8])
+	montmul(&t[4], &t[0], &t[1])
+	chain(&t[0], 12, &t[15])
+	chain(&t[0], 7, &t[7])
+	chain(&t[0], 4, &t[1])
+	chain(&t[0], 6, &t[6])
+	chain(&t[0], 7, &t[11])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 2, &t[8])
+	chain(&t[0], 6, &t[3])
+	chain(&t[0], 6, &t[3])
+	chain(&t[0], 6, &t[9])
+	chain(&t[0], 3, &t[8])
+	chain(&t[0], 7, &t[3])
+	chain(&t[0], 4, &t[3])
+	chain(&t[0], 6, &t[7])
+	chain(&t[0], 6, &t[14])
+	chain(&t[0], 3, &t[13])
+	chain(&t[0], 8, &t[3])
+	chain(&t[0], 7, &t[11])
+	chain(&t[0], 5, &t[12])
+	chain(&t[0], 6, &t[3])
+	chain(&t[0], 6, &t[5])
+	chain(&t[0], 4, &t[9])
+	chain(&t[0], 8, &t[5])
+	chain(&t[0], 4, &t[3])
+	chain(&t[0], 7, &t[11])
+	chain(&t[0], 9, &t[10])
+	chain(&t[0], 2, &t[8])
+	chain(&t[0], 5, &t[6])
+	chain(&t[0], 7, &t[1])
+	chain(&t[0], 7, &t[9])
+	chain(&t[0], 6, &t[11])
+	chain(&t[0], 5, &t[5])
+	chain(&t[0], 5, &t[10])
+	chain(&t[0], 5, &t[10])
+	chain(&t[0], 8, &t[3])
+	chain(&t[0], 7, &t[2])
+	chain(&t[0], 9, &t[7])
+	chain(&t[0], 5, &t[3])
+	chain(&t[0], 3, &t[8])
+	chain(&t[0], 8, &t[7])
+	chain(&t[0], 3, &t[8])
+	chain(&t[0], 7, &t[9])
+	chain(&t[0], 9, &t[7])
+	chain(&t[0], 6, &t[2])
+	chain(&t[0], 6, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 4, &t[3])
+	chain(&t[0], 3, &t[8])
+	chain(&t[0], 8, &t[2])
+	chain(&t[0], 7, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 4, &t[7])
+	chain(&t[0], 4, &t[6])
+	chain(&t[0], 7, &t[4])
+	chain(&t[0], 5, &t[5])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 5, &t[4])
+	chain(&t[0], 4, &t[3])
+	chain(&t[0], 6, &t[2])
+	chain(&t[0], 4, &t[1])
+	montsquare(c, &t[0])
 }
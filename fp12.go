@@ -33,7 +33,7 @@ func (fp *Fp12) NewElement() *Fe12 {
 
 func (fp *Fp12) NewElementFromBytes(f *Fe12, b []byte) error {
 	if len(b) < 576 {
-		return fmt.Errorf("input string should be larger than 576 bytes")
+		return fmt.Errorf("%w: input string should be larger than 576 bytes", ErrInvalidLength)
 	}
 	if err := fp.f.NewElementFromBytes(&f[1], b[:288]); err != nil {
 		return err
@@ -33,7 +33,7 @@ func (fp *Fp2) NewElement() *Fe2 {
 
 func (fp *Fp2) NewElementFromBytes(fe *Fe2, b []byte) error {
 	if len(b) < 96 {
-		return fmt.Errorf("input string should be larger than 96 bytes")
+		return fmt.Errorf("%w: input string should be larger than 96 bytes", ErrInvalidLength)
 	}
 	if err := fp.f.NewElementFromBytes(&fe[1], b[:48]); err != nil {
 		return err
@@ -175,21 +175,85 @@ func (fp *Fp2) FrobeniousMap(c, a *Fe2, power uint) {
 	fp.f.Mul(&c[1], &a[1], &frobeniusCoeffs2[power%2])
 }
 
+// LexicographicallyLargest returns true if a is larger than its negation
+// comparing the imaginary part first, that is, the highest degree non zero
+// coefficient decides. It is the sign flag of compressed G2 point encoding.
+func (fp *Fp2) LexicographicallyLargest(a *Fe2) bool {
+	if a[1].IsZero() {
+		return fp.f.LexicographicallyLargest(&a[0])
+	}
+	return fp.f.LexicographicallyLargest(&a[1])
+}
+
+// Sgn0 is the sgn0 function of RFC 9380 for degree two extension,
+// sgn0(a0) || (a0 == 0 && sgn0(a1)).
+func (fp *Fp2) Sgn0(a *Fe2) bool {
+	if a[0].IsZero() {
+		return fp.f.Sgn0(&a[1])
+	}
+	return fp.f.Sgn0(&a[0])
+}
+
+// IsSquare reports whether a is a quadratic residue. An element of Fp2 is
+// a square if and only if its norm is a square in Fp.
+func (fp *Fp2) IsSquare(a *Fe2) bool {
+	t := fp.t
+	fp.f.Square(t[0], &a[0])
+	fp.f.Square(t[1], &a[1])
+	fp.f.Add(t[0], t[0], t[1])
+	return fp.f.IsSquare(t[0])
+}
+
+// Sqrt computes square root of a with the complex method. Given
+// a = a0 + a1*u, gamma = sqrt(a0^2 + a1^2) and delta = (a0 + gamma) / 2
+// root is sqrt(delta) + (a1 / (2 * sqrt(delta)))*u. When delta is not a square
+// (a0 - gamma) / 2 = -a1^2 / (4 * delta) is, and root is derived from
+// sqrt(-delta) with a single inversion.
 func (fp *Fp2) Sqrt(c, a *Fe2) bool {
-	u, x0, a1, alpha := &Fe2{}, &Fe2{}, &Fe2{}, &Fe2{}
-	fp.Copy(u, a)
-	fp.Exp(a1, a, pMinus3Over4)
-	fp.Square(alpha, a1)
-	fp.Mul(alpha, alpha, a)
-	fp.Mul(x0, a1, a)
-	if fp.Equal(alpha, negativeOne2) {
-		fp.f.Neg(&c[0], &x0[1])
-		fp.f.Copy(&c[1], &x0[0])
+	f := fp.f
+	if a[1].IsZero() {
+		t := new(Fe)
+		if f.Sqrt(t, &a[0]) {
+			f.Copy(&c[0], t)
+			f.Copy(&c[1], &FpZero)
+			return true
+		}
+		f.Neg(t, &a[0])
+		if f.Sqrt(t, t) {
+			f.Copy(&c[1], t)
+			f.Copy(&c[0], &FpZero)
+			return true
+		}
+		return false
+	}
+	gamma, delta, t, s := new(Fe), new(Fe), new(Fe), new(Fe)
+	// gamma = sqrt(a0^2 + a1^2)
+	f.Square(gamma, &a[0])
+	f.Square(t, &a[1])
+	f.Add(gamma, gamma, t)
+	if !f.Sqrt(gamma, gamma) {
+		return false
+	}
+	// delta = (a0 + gamma) / 2
+	f.Add(delta, &a[0], gamma)
+	f.Mul(delta, delta, twoInv)
+	// t = delta ^ ((p-3)/4), s = delta ^ ((p+1)/4)
+	f.sqrtAddchain(t, delta)
+	f.Mul(s, t, delta)
+	// legendre = t * s
+	f.Mul(gamma, t, s)
+	if gamma.Equals(&FpOne) {
+		// c0 = sqrt(delta), c1 = a1 / (2 * c0) = a1 * t / 2
+		f.Mul(t, t, &a[1])
+		f.Mul(&c[1], t, twoInv)
+		f.Copy(&c[0], s)
 		return true
 	}
-	fp.Add(alpha, alpha, &Fp2One)
-	fp.Exp(alpha, alpha, pMinus1Over2)
-	fp.Mul(c, alpha, x0)
-	fp.Square(alpha, c)
-	return fp.Equal(alpha, u)
+	// s = sqrt(-delta), c0 = a1 * s / (2 * delta), c1 = -s
+	f.Inverse(delta, delta)
+	f.Mul(delta, delta, s)
+	f.Mul(delta, delta, &a[1])
+	f.Mul(&c[0], delta, twoInv)
+	f.Neg(&c[1], s)
+	return true
 }
@@ -33,7 +33,7 @@ func (fp *Fp6) NewElement() *Fe6 {
 
 func (fp *Fp6) NewElementFromBytes(c *Fe6, b []byte) error {
 	if len(b) < 288 {
-		return fmt.Errorf("input string should be larger than 288 bytes")
+		return fmt.Errorf("%w: input string should be larger than 288 bytes", ErrInvalidLength)
 	}
 	if err := fp.f.NewElementFromBytes(&c[2], b[:96]); err != nil {
 		return err
@@ -3,6 +3,7 @@ package bls
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
 	"math/big"
 	"testing"
 )
@@ -54,6 +55,17 @@ func TestFp(t *testing.T) {
 				t.Errorf("bad encoding or decoding\n")
 			}
 		})
+		t.Run("6", func(t *testing.T) {
+			if err := field.NewElementFromBytes(&Fe{}, modulus.Bytes()); !errors.Is(err, ErrNonCanonical) {
+				t.Errorf("modulus should not be decoded\n")
+			}
+			if _, err := field.NewElementFromBig(modulus.Big()); !errors.Is(err, ErrNonCanonical) {
+				t.Errorf("modulus should not be decoded\n")
+			}
+			if _, err := field.NewElementFromString("0xzz"); err == nil {
+				t.Errorf("bad hex string should not be decoded\n")
+			}
+		})
 	})
 	t.Run("Addition", func(t *testing.T) {
 		var a, b, c, u, v *Fe
@@ -254,6 +266,11 @@ func TestFp(t *testing.T) {
 				t.Fatalf("Bad inversion 2")
 			}
 		}
+		u = &Fe{}
+		field.Inverse(u, field.One())
+		if !field.Equal(u, field.One()) {
+			t.Fatalf("Bad inversion of one")
+		}
 	})
 	t.Run("Sqrt", func(t *testing.T) {
 		r := &Fe{}
@@ -271,6 +288,72 @@ func TestFp(t *testing.T) {
 			if !field.Equal(rr, aa) {
 				t.Fatalf("bad sqrt 3")
 			}
+			field.Exp(rr, aa, pPlus1Over4)
+			if !field.Equal(rr, r) {
+				t.Fatalf("bad sqrt 4")
+			}
+			if !field.SqrtCT(rr, aa) || !field.Equal(rr, r) {
+				t.Fatalf("bad sqrt 5")
+			}
+			field.Mul(aa, aa, nonResidue1)
+			if field.Sqrt(r, aa) || field.SqrtCT(r, aa) {
+				t.Fatalf("bad sqrt 6")
+			}
+		}
+	})
+	t.Run("LexicographicallyLargest", func(t *testing.T) {
+		if field.LexicographicallyLargest(field.Zero()) || field.LexicographicallyLargest(field.One()) || !field.LexicographicallyLargest(negativeOne) {
+			t.Fatalf("bad lexicographic sign 1")
+		}
+		for j := 0; j < n; j++ {
+			a, _ := field.RandElement(&Fe{}, rand.Reader)
+			b := &Fe{}
+			field.Neg(b, a)
+			if field.LexicographicallyLargest(a) == field.LexicographicallyLargest(b) {
+				t.Fatalf("bad lexicographic sign 2")
+			}
+		}
+	})
+	t.Run("Sgn0", func(t *testing.T) {
+		// p - 1 is even
+		if field.Sgn0(field.Zero()) || !field.Sgn0(field.One()) || field.Sgn0(negativeOne) {
+			t.Fatalf("bad sgn0 1")
+		}
+		for j := 0; j < n; j++ {
+			a, _ := field.RandElement(&Fe{}, rand.Reader)
+			b := &Fe{}
+			field.Neg(b, a)
+			if field.Sgn0(a) == field.Sgn0(b) {
+				t.Fatalf("bad sgn0 2")
+			}
+			field.Demont(b, a)
+			if field.Sgn0(a) != b.IsOdd() {
+				t.Fatalf("bad sgn0 3")
+			}
+		}
+	})
+	t.Run("Legendre", func(t *testing.T) {
+		if field.Legendre(zero) != 0 || !field.IsSquare(zero) {
+			t.Fatalf("bad legendre symbol for zero")
+		}
+		if field.Legendre(nonResidue1) != -1 || field.IsSquare(nonResidue1) {
+			t.Fatalf("bad legendre symbol for non residue")
+		}
+		for j := 0; j < n; j++ {
+			a, _ := field.RandElement(&Fe{}, rand.Reader)
+			u, v := &Fe{}, &Fe{}
+			field.Exp(u, a, pMinus1Over2)
+			expected := -1
+			if field.Equal(u, field.One()) {
+				expected = 1
+			}
+			if field.Legendre(a) != expected {
+				t.Fatalf("bad legendre symbol 1")
+			}
+			field.Square(v, a)
+			if field.Legendre(v) != 1 || !field.IsSquare(v) {
+				t.Fatalf("bad legendre symbol 2")
+			}
 		}
 	})
 }
@@ -289,6 +372,9 @@ func TestFp2(t *testing.T) {
 		if !bytes.Equal(in, field.ToBytes(fe)) {
 			t.Errorf("bad encoding\n")
 		}
+		if err := field.NewElementFromBytes(fe, in[:95]); !errors.Is(err, ErrInvalidLength) {
+			t.Errorf("short input should not be decoded\n")
+		}
 	})
 	t.Run("Multiplication", func(t *testing.T) {
 		var a, b, c, u, v, w *Fe2
@@ -368,20 +454,74 @@ func TestFp2(t *testing.T) {
 	})
 	t.Run("Sqrt", func(t *testing.T) {
 		r := &Fe2{}
-		if field.Sqrt(r, nonResidue2) {
+		if field.Sqrt(r, nonResidue2) || field.IsSquare(nonResidue2) {
 			t.Fatalf("bad sqrt 1")
 		}
 		for j := 0; j < n; j++ {
 			a, _ := field.RandElement(&Fe2{}, rand.Reader)
 			aa, rr, r := &Fe2{}, &Fe2{}, &Fe2{}
 			field.Square(aa, a)
-			if !field.Sqrt(r, aa) {
+			if !field.Sqrt(r, aa) || !field.IsSquare(aa) {
 				t.Fatalf("bad sqrt 2")
 			}
 			field.Square(rr, r)
 			if !field.Equal(rr, aa) {
 				t.Fatalf("bad sqrt 3")
 			}
+			field.Mul(aa, aa, nonResidue2)
+			if field.Sqrt(r, aa) || field.IsSquare(aa) {
+				t.Fatalf("bad sqrt 4")
+			}
+			// elements of base field are squares in Fp2
+			field.Copy(aa, &Fe2{a[0]})
+			if !field.Sqrt(r, aa) {
+				t.Fatalf("bad sqrt 5")
+			}
+			field.Square(rr, r)
+			if !field.Equal(rr, aa) {
+				t.Fatalf("bad sqrt 6")
+			}
+		}
+	})
+	t.Run("LexicographicallyLargest", func(t *testing.T) {
+		if field.LexicographicallyLargest(field.Zero()) || field.LexicographicallyLargest(field.One()) || !field.LexicographicallyLargest(negativeOne2) {
+			t.Fatalf("bad lexicographic sign 1")
+		}
+		for j := 0; j < n; j++ {
+			a, _ := field.RandElement(&Fe2{}, rand.Reader)
+			b := &Fe2{}
+			field.Neg(b, a)
+			if field.LexicographicallyLargest(a) == field.LexicographicallyLargest(b) {
+				t.Fatalf("bad lexicographic sign 2")
+			}
+			if field.LexicographicallyLargest(a) != field.f.LexicographicallyLargest(&a[1]) {
+				t.Fatalf("bad lexicographic sign 3")
+			}
+			field.Copy(a, &Fe2{a[0]})
+			if field.LexicographicallyLargest(a) != field.f.LexicographicallyLargest(&a[0]) {
+				t.Fatalf("bad lexicographic sign 4")
+			}
+		}
+	})
+	t.Run("Sgn0", func(t *testing.T) {
+		if field.Sgn0(field.Zero()) || !field.Sgn0(field.One()) || field.Sgn0(negativeOne2) {
+			t.Fatalf("bad sgn0 1")
+		}
+		for j := 0; j < n; j++ {
+			a, _ := field.RandElement(&Fe2{}, rand.Reader)
+			b := &Fe2{}
+			field.Neg(b, a)
+			if field.Sgn0(a) == field.Sgn0(b) {
+				t.Fatalf("bad sgn0 2")
+			}
+			// real part decides unless it is zero
+			if field.Sgn0(a) != field.f.Sgn0(&a[0]) {
+				t.Fatalf("bad sgn0 3")
+			}
+			field.Copy(a, &Fe2{Fe{}, a[1]})
+			if field.Sgn0(a) != field.f.Sgn0(&a[1]) {
+				t.Fatalf("bad sgn0 4")
+			}
 		}
 	})
 }
@@ -601,6 +741,11 @@ func TestFp12(t *testing.T) {
 				t.Fatalf("Bad inversion, expected to equal r1")
 			}
 		}
+		u = &Fe12{}
+		field.Inverse(u, field.One())
+		if !field.Equal(u, field.One()) {
+			t.Fatalf("Bad inversion of one")
+		}
 	})
 	t.Run("MulBy014", func(t *testing.T) {
 		fq2 := field.f.f
@@ -686,6 +831,18 @@ func BenchmarkFp(t *testing.B) {
 			field.Exp(&c, &a, e)
 		}
 	})
+	t.Run("Sqrt", func(t *testing.B) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			field.Sqrt(&c, &a)
+		}
+	})
+	t.Run("Legendre", func(t *testing.B) {
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			field.Legendre(&a)
+		}
+	})
 }
 
 func BenchmarkFp2(t *testing.B) {
@@ -736,4 +893,11 @@ func BenchmarkFp2(t *testing.B) {
 			field.Exp(&c, &a, e)
 		}
 	})
+	t.Run("Sqrt", func(t *testing.B) {
+		field.Square(&a, &a)
+		t.ResetTimer()
+		for i := 0; i < t.N; i++ {
+			field.Sqrt(&c, &a)
+		}
+	})
 }
@@ -0,0 +1,242 @@
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+)
+
+// Fr is an element of scalar field, integers modulo curve order q. Elements
+// are kept in Montgomery form and conversions take place at FromBig,
+// FromBytes and ToBig, ToBytes.
+type Fr [4]uint64
+
+// q in limbs
+var frModulus = Fr{0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48}
+
+// frInp = -q^(-1) mod 2^64
+var frInp uint64 = 0xfffffffeffffffff
+
+// frR1 = 2^256 mod q
+var frR1 = Fr{0x00000001fffffffe, 0x5884b7fa00034802, 0x998c4fefecbc4ff5, 0x1824b159acc5056f}
+
+// frR2 = 2^512 mod q
+var frR2 = Fr{0xc999e990f3f29c6d, 0x2b6cedcb87925c23, 0x05d314967254398f, 0x0748d9d99f59ff11}
+
+// q - 2
+var qMinus2 = new(big.Int).Sub(q, big.NewInt(2))
+
+func NewFr() *Fr {
+	return &Fr{}
+}
+
+func (e *Fr) Set(a *Fr) *Fr {
+	*e = *a
+	return e
+}
+
+func (e *Fr) Zero() *Fr {
+	*e = Fr{}
+	return e
+}
+
+func (e *Fr) One() *Fr {
+	*e = frR1
+	return e
+}
+
+func (e *Fr) SetUint64(a uint64) *Fr {
+	*e = Fr{a}
+	e.Mul(e, &frR2)
+	return e
+}
+
+// FromBig sets e to a reduced modulo curve order.
+func (e *Fr) FromBig(a *big.Int) *Fr {
+	b := new(big.Int).Mod(a, q)
+	*e = Fr{}
+	words := b.Bits()
+	for i := 0; i < len(words); i++ {
+		e[i] = uint64(words[i])
+	}
+	e.Mul(e, &frR2)
+	return e
+}
+
+// FromBytes sets e to big endian integer reduced modulo curve order.
+func (e *Fr) FromBytes(in []byte) *Fr {
+	return e.FromBig(new(big.Int).SetBytes(in))
+}
+
+// FromBytesCanonical sets e to 32 bytes big endian integer which must be
+// less than curve order.
+func (e *Fr) FromBytesCanonical(in []byte) (*Fr, error) {
+	if len(in) != 32 {
+		return nil, ErrInvalidLength
+	}
+	a := new(big.Int).SetBytes(in)
+	if a.Cmp(q) >= 0 {
+		return nil, fmt.Errorf("%w: scalar is not less than curve order", ErrNonCanonical)
+	}
+	return e.FromBig(a), nil
+}
+
+func (e *Fr) ToBig() *big.Int {
+	a := new(Fr).fromMont(e)
+	out := new(big.Int)
+	for i := 3; i >= 0; i-- {
+		out.Lsh(out, 64)
+		out.Or(out, new(big.Int).SetUint64(a[i]))
+	}
+	return out
+}
+
+// ToBytes returns 32 bytes big endian encoding of e.
+func (e *Fr) ToBytes() []byte {
+	a := new(Fr).fromMont(e)
+	out := make([]byte, 32)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			out[31-i*8-j] = byte(a[i] >> uint(8*j))
+		}
+	}
+	return out
+}
+
+func (e *Fr) String() string {
+	return fmt.Sprintf("0x%064x", e.ToBig())
+}
+
+func (e *Fr) Rand(r io.Reader) (*Fr, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	a, err := rand.Int(r, q)
+	if err != nil {
+		return nil, err
+	}
+	return e.FromBig(a), nil
+}
+
+func (e *Fr) IsZero() bool {
+	return (e[0] | e[1] | e[2] | e[3]) == 0
+}
+
+func (e *Fr) IsOne() bool {
+	return *e == frR1
+}
+
+func (e *Fr) Equal(a *Fr) bool {
+	return *e == *a
+}
+
+func (e *Fr) Add(a, b *Fr) {
+	var c uint64
+	e[0], c = bits.Add64(a[0], b[0], 0)
+	e[1], c = bits.Add64(a[1], b[1], c)
+	e[2], c = bits.Add64(a[2], b[2], c)
+	e[3], _ = bits.Add64(a[3], b[3], c)
+	e.reduce()
+}
+
+func (e *Fr) Double(a *Fr) {
+	e.Add(a, a)
+}
+
+func (e *Fr) Sub(a, b *Fr) {
+	var br uint64
+	e[0], br = bits.Sub64(a[0], b[0], 0)
+	e[1], br = bits.Sub64(a[1], b[1], br)
+	e[2], br = bits.Sub64(a[2], b[2], br)
+	e[3], br = bits.Sub64(a[3], b[3], br)
+	if br != 0 {
+		var c uint64
+		e[0], c = bits.Add64(e[0], frModulus[0], 0)
+		e[1], c = bits.Add64(e[1], frModulus[1], c)
+		e[2], c = bits.Add64(e[2], frModulus[2], c)
+		e[3], _ = bits.Add64(e[3], frModulus[3], c)
+	}
+}
+
+func (e *Fr) Neg(a *Fr) {
+	if a.IsZero() {
+		e.Zero()
+		return
+	}
+	e.Sub(&frModulus, a)
+}
+
+// Mul sets e to a * b with Montgomery multiplication.
+func (e *Fr) Mul(a, b *Fr) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c, hi, lo uint64
+		for j := 0; j < 4; j++ {
+			hi, lo = bits.Mul64(a[j], b[i])
+			lo, c0 := bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, c, 0)
+			hi += c0
+			t[j], c = lo, hi
+		}
+		t[4], c = bits.Add64(t[4], c, 0)
+		t[5] = c
+		m := t[0] * frInp
+		hi, lo = bits.Mul64(m, frModulus[0])
+		_, c = bits.Add64(lo, t[0], 0)
+		c += hi
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, frModulus[j])
+			lo, c0 := bits.Add64(lo, t[j], 0)
+			hi += c0
+			lo, c0 = bits.Add64(lo, c, 0)
+			hi += c0
+			t[j-1], c = lo, hi
+		}
+		t[3], c = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + c
+	}
+	e[0], e[1], e[2], e[3] = t[0], t[1], t[2], t[3]
+	e.reduce()
+}
+
+func (e *Fr) Square(a *Fr) {
+	e.Mul(a, a)
+}
+
+func (e *Fr) Exp(a *Fr, n *big.Int) {
+	z := new(Fr).One()
+	b := new(Fr).Set(a)
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		z.Square(z)
+		if n.Bit(i) == 1 {
+			z.Mul(z, b)
+		}
+	}
+	e.Set(z)
+}
+
+// Inverse sets e to inverse of a. Inverse of zero is zero.
+func (e *Fr) Inverse(a *Fr) {
+	e.Exp(a, qMinus2)
+}
+
+func (e *Fr) fromMont(a *Fr) *Fr {
+	e.Mul(a, &Fr{1})
+	return e
+}
+
+// reduce subtracts modulus once if e is not less than modulus.
+func (e *Fr) reduce() {
+	var t Fr
+	var br uint64
+	t[0], br = bits.Sub64(e[0], frModulus[0], 0)
+	t[1], br = bits.Sub64(e[1], frModulus[1], br)
+	t[2], br = bits.Sub64(e[2], frModulus[2], br)
+	t[3], br = bits.Sub64(e[3], frModulus[3], br)
+	if br == 0 {
+		*e = t
+	}
+}
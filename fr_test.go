@@ -0,0 +1,97 @@
+package bls
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFr(t *testing.T) {
+	t.Run("Conversion", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a := randScalar(q)
+			e := new(Fr).FromBig(a)
+			if e.ToBig().Cmp(a) != 0 {
+				t.Fatalf("bad big conversion")
+			}
+			b := new(Fr).FromBytes(e.ToBytes())
+			if !b.Equal(e) {
+				t.Fatalf("bad bytes conversion")
+			}
+		}
+		if !new(Fr).FromBig(q).IsZero() {
+			t.Fatalf("q should be reduced to zero")
+		}
+		if !new(Fr).SetUint64(1).IsOne() {
+			t.Fatalf("bad one")
+		}
+	})
+	t.Run("Arithmetic", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b := randScalar(q), randScalar(q)
+			ea, eb := new(Fr).FromBig(a), new(Fr).FromBig(b)
+			c, expected := new(Fr), new(big.Int)
+			c.Add(ea, eb)
+			if c.ToBig().Cmp(expected.Mod(expected.Add(a, b), q)) != 0 {
+				t.Fatalf("bad addition")
+			}
+			c.Sub(ea, eb)
+			if c.ToBig().Cmp(expected.Mod(expected.Sub(a, b), q)) != 0 {
+				t.Fatalf("bad subtraction")
+			}
+			c.Neg(ea)
+			if c.ToBig().Cmp(expected.Mod(expected.Neg(a), q)) != 0 {
+				t.Fatalf("bad negation")
+			}
+			c.Mul(ea, eb)
+			if c.ToBig().Cmp(expected.Mod(expected.Mul(a, b), q)) != 0 {
+				t.Fatalf("bad multiplication")
+			}
+			c.Square(ea)
+			if c.ToBig().Cmp(expected.Mod(expected.Mul(a, a), q)) != 0 {
+				t.Fatalf("bad squaring")
+			}
+			c.Exp(ea, b)
+			if c.ToBig().Cmp(expected.Exp(a, b, q)) != 0 {
+				t.Fatalf("bad exponentiation")
+			}
+			c.Inverse(ea)
+			c.Mul(c, ea)
+			if !c.IsOne() {
+				t.Fatalf("bad inversion")
+			}
+		}
+		c := new(Fr)
+		c.Sub(c, new(Fr).One())
+		if c.ToBig().Cmp(new(big.Int).Sub(q, big.NewInt(1))) != 0 {
+			t.Fatalf("bad subtraction with borrow")
+		}
+	})
+	t.Run("Canonical", func(t *testing.T) {
+		in := make([]byte, 32)
+		copy(in, q.Bytes())
+		if _, err := new(Fr).FromBytesCanonical(in); !errors.Is(err, ErrNonCanonical) {
+			t.Fatalf("expected %v, got %v", ErrNonCanonical, err)
+		}
+		if _, err := new(Fr).FromBytesCanonical(in[1:]); !errors.Is(err, ErrInvalidLength) {
+			t.Fatalf("expected %v, got %v", ErrInvalidLength, err)
+		}
+		in[31] = 0
+		e, err := new(Fr).FromBytesCanonical(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(e.ToBytes(), in) {
+			t.Fatalf("bad encoding")
+		}
+	})
+}
+
+func BenchmarkFrMul(t *testing.B) {
+	a, b := new(Fr).FromBig(randScalar(q)), new(Fr).FromBig(randScalar(q))
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		a.Mul(a, b)
+	}
+}
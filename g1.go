@@ -2,6 +2,7 @@ package bls
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 )
 
@@ -32,39 +33,54 @@ func NewG1(f *Fp) *G1 {
 
 func (g *G1) FromUncompressed(uncompressed []byte) (*PointG1, error) {
 	if len(uncompressed) < 96 {
-		return nil, fmt.Errorf("input string should be equal or larger than 96")
+		return nil, fmt.Errorf("%w: input string should be equal or larger than 96", ErrInvalidLength)
+	}
+	p, err := g.FromUncompressedUnchecked(uncompressed[:96])
+	if err != nil {
+		return nil, err
+	}
+	if !g.IsOnCurve(p) {
+		return nil, ErrNotOnCurve
+	}
+	if !g.isTorsionFree(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return p, nil
+}
+
+// FromUncompressedUnchecked decodes a point from exactly 96 bytes without
+// checking whether it is on curve and in correct subgroup. It must be used
+// only for points which are known to be valid, such as those previously
+// encoded by this node, and never for untrusted input.
+func (g *G1) FromUncompressedUnchecked(uncompressed []byte) (*PointG1, error) {
+	if len(uncompressed) != 96 {
+		return nil, ErrInvalidLength
 	}
 	var in [96]byte
-	copy(in[:], uncompressed[:96])
+	copy(in[:], uncompressed)
 	if in[0]&(1<<7) != 0 {
-		return nil, fmt.Errorf("compression flag should be zero")
+		return nil, fmt.Errorf("%w: compression flag should be zero", ErrNonCanonical)
 	}
 	if in[0]&(1<<5) != 0 {
-		return nil, fmt.Errorf("sort flag should be zero")
-	}
-	in[0] &= 0x1f
-	x, y := &Fe{}, &Fe{}
-	if err := g.f.NewElementFromBytes(x, in[:48]); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: sort flag should be zero", ErrNonCanonical)
 	}
-	if err := g.f.NewElementFromBytes(y, in[48:]); err != nil {
-		return nil, err
-	}
-	if in[0]&(1<<6) == 1 {
-		if !(x.IsZero() && y.IsZero()) {
-			return nil, fmt.Errorf("input string should be zero when infinity flag is set")
+	if in[0]&(1<<6) != 0 {
+		in[0] &= 0x3f
+		for i := 0; i < 96; i++ {
+			if in[i] != 0 {
+				return nil, fmt.Errorf("%w: input string should be zero when infinity flag is set", ErrNonCanonical)
+			}
 		}
+		return g.Zero(), nil
 	}
 	p := &PointG1{}
-	g.f.Copy(&p[0], x)
-	g.f.Copy(&p[1], y)
-	g.f.Copy(&p[2], &FpOne)
-	if !g.IsOnCurve(p) {
-		return nil, fmt.Errorf("point is not on curve")
+	if err := g.f.NewElementFromBytes(&p[0], in[:48]); err != nil {
+		return nil, err
 	}
-	if !g.isTorsionFree(p) {
-		return nil, fmt.Errorf("point is not on correct subgroup")
+	if err := g.f.NewElementFromBytes(&p[1], in[48:]); err != nil {
+		return nil, err
 	}
+	g.f.Copy(&p[2], &FpOne)
 	return p, nil
 }
 
@@ -73,6 +89,7 @@ func (g *G1) ToUncompressed(p *PointG1) []byte {
 	g.Affine(p)
 	if g.IsZero(p) {
 		out[0] |= 1 << 6
+		return out
 	}
 	copy(out[:48], g.f.ToBytes(&p[0]))
 	copy(out[48:], g.f.ToBytes(&p[1]))
@@ -81,51 +98,69 @@ func (g *G1) ToUncompressed(p *PointG1) []byte {
 
 func (g *G1) FromCompressed(compressed []byte) (*PointG1, error) {
 	if len(compressed) < 48 {
-		return nil, fmt.Errorf("input string should be equal or larger than 48")
+		return nil, ErrInvalidLength
+	}
+	return g.FromCompressedStrict(compressed[:48])
+}
+
+// FromCompressedStrict decodes a point from exactly 48 bytes. Encodings
+// with a coordinate larger than modulus or with infinity and sign flags set
+// together are rejected with ErrNonCanonical.
+func (g *G1) FromCompressedStrict(in []byte) (*PointG1, error) {
+	p, err := g.FromCompressedUnchecked(in)
+	if err != nil {
+		return nil, err
+	}
+	if !g.isTorsionFree(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return p, nil
+}
+
+// FromCompressedUnchecked is FromCompressedStrict without subgroup check.
+// It must not be used for untrusted input.
+func (g *G1) FromCompressedUnchecked(in []byte) (*PointG1, error) {
+	if len(in) != 48 {
+		return nil, ErrInvalidLength
 	}
-	var in [48]byte
-	copy(in[:], compressed[:])
 	if in[0]&(1<<7) == 0 {
-		return nil, fmt.Errorf("bad compression")
+		return nil, ErrNonCanonical
 	}
+	a := in[0]&(1<<5) != 0
 	if in[0]&(1<<6) != 0 {
-		in[0] &= 0x3f
-		for i := 0; i < 48; i++ {
+		if a || in[0]&0x1f != 0 {
+			return nil, ErrNonCanonical
+		}
+		for i := 1; i < 48; i++ {
 			if in[i] != 0 {
-				return nil, fmt.Errorf("bad infinity compression")
+				return nil, ErrNonCanonical
 			}
 		}
 		return g.Zero(), nil
 	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
-	x := &Fe{}
-	if err := g.f.NewElementFromBytes(x, in[:]); err != nil {
-		return nil, err
+	var buf [48]byte
+	copy(buf[:], in)
+	buf[0] &= 0x1f
+	x := new(Fe).FromBytes(buf[:])
+	if !g.f.Valid(x) {
+		return nil, ErrNonCanonical
 	}
+	g.f.Mont(x, x)
 	// solve curve equation
 	y := &Fe{}
 	g.f.Square(y, x)
 	g.f.Mul(y, y, x)
 	g.f.Add(y, y, b)
 	if ok := g.f.Sqrt(y, y); !ok {
-		return nil, fmt.Errorf("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
-	// select lexicographically, should be in mont reduced form
-	negY, negYn, yn := &Fe{}, &Fe{}, &Fe{}
-	g.f.Demont(yn, y)
-	g.f.Neg(negY, y)
-	g.f.Neg(negYn, yn)
-	if yn.Cmp(negYn) > -1 != a {
-		g.f.Copy(y, negY)
+	if g.f.LexicographicallyLargest(y) != a {
+		g.f.Neg(y, y)
 	}
 	p := &PointG1{}
 	g.f.Copy(&p[0], x)
 	g.f.Copy(&p[1], y)
 	g.f.Copy(&p[2], &FpOne)
-	if !g.isTorsionFree(p) {
-		return nil, fmt.Errorf("point is not on correct subgroup")
-	}
 	return p, nil
 }
 
@@ -136,11 +171,7 @@ func (g *G1) ToCompressed(p *PointG1) []byte {
 		out[0] |= 1 << 6
 	} else {
 		copy(out[:], g.f.ToBytes(&p[0]))
-		y, negY := &Fe{}, &Fe{}
-		g.f.Copy(y, &p[1])
-		g.f.Demont(y, y)
-		g.f.Neg(negY, y)
-		if y.Cmp(negY) > 0 {
+		if g.f.LexicographicallyLargest(&p[1]) {
 			out[0] |= 1 << 5
 		}
 	}
@@ -148,16 +179,52 @@ func (g *G1) ToCompressed(p *PointG1) []byte {
 	return out
 }
 
-func (g *G1) fromRawUnchecked(in []byte) *PointG1 {
-	p := &PointG1{}
-	if err := g.f.NewElementFromBytes(&p[0], in[:48]); err != nil {
-		panic(err)
+// ToMont encodes affine coordinates of the point in Montgomery form so that
+// FromMontUnchecked can load it back without any field conversion. Point at
+// infinity is encoded as in uncompressed form. This encoding is internal to
+// this implementation and should only be used for local storage.
+func (g *G1) ToMont(p *PointG1) []byte {
+	out := make([]byte, 96)
+	g.Affine(p)
+	if g.IsZero(p) {
+		out[0] |= 1 << 6
+		return out
 	}
-	if err := g.f.NewElementFromBytes(&p[1], in[48:]); err != nil {
-		panic(err)
+	copy(out[:48], p[0].Bytes())
+	copy(out[48:], p[1].Bytes())
+	return out
+}
+
+// FromMontUnchecked decodes a point encoded with ToMont. Only the range of
+// coordinates is checked, it must not be used for untrusted input.
+func (g *G1) FromMontUnchecked(in []byte) (*PointG1, error) {
+	if len(in) != 96 {
+		return nil, ErrInvalidLength
+	}
+	if in[0]&(1<<6) != 0 {
+		for i := 1; i < 96; i++ {
+			if in[i] != 0 {
+				return nil, ErrNonCanonical
+			}
+		}
+		if in[0] != 1<<6 {
+			return nil, ErrNonCanonical
+		}
+		return g.Zero(), nil
+	}
+	p := &PointG1{}
+	p[0].FromBytes(in[:48])
+	p[1].FromBytes(in[48:])
+	if !g.f.Valid(&p[0]) || !g.f.Valid(&p[1]) {
+		return nil, ErrNonCanonical
 	}
 	g.f.Copy(&p[2], &FpOne)
-	return p
+	return p, nil
+}
+
+// InCorrectSubgroup checks whether given point is in the prime order subgroup.
+func (g *G1) InCorrectSubgroup(p *PointG1) bool {
+	return g.isTorsionFree(p)
 }
 
 func (g *G1) isTorsionFree(p *PointG1) bool {
@@ -354,46 +421,84 @@ func (g *G1) MulByCofactor(c, p *PointG1) {
 	g.MulScalar(c, p, cofactorG1)
 }
 
-// func (g *G1) MultiExp(r *PointG1, points []*PointG1, powers []*big.Int) (*PointG1, error) {
-// 	if len(points) != len(powers) {
-// 		return nil, fmt.Errorf("point and scalar vectors should be in same length")
-// 	}
-// 	var c uint = 3
-// 	if len(powers) > 32 {
-// 		c = uint(math.Ceil(math.Log10(float64(len(powers)))))
-// 	}
-// 	bucket_size, numBits := (1<<c)-1, q.BitLen()
-// 	windows := make([]PointG1, numBits/int(c)+1)
-// 	bucket := make([]PointG1, bucket_size)
-// 	acc, sum, zero := g.Zero(), g.Zero(), g.Zero()
-// 	s := new(big.Int)
-// 	for i, m := 0, 0; i <= numBits; i, m = i+int(c), m+1 {
-// 		for i := 0; i < bucket_size; i++ {
-// 			g.Copy(&bucket[i], zero)
-// 		}
-// 		for j := 0; j < len(powers); j++ {
-// 			s = powers[j]
-// 			index := s.Uint64() & uint64(bucket_size)
-// 			if index != 0 {
-// 				g.Add(&bucket[index-1], &bucket[index-1], points[j])
-// 			}
-// 			s.Rsh(s, c)
-// 		}
-// 		g.Copy(acc, zero)
-// 		g.Copy(sum, zero)
-// 		for k := bucket_size - 1; k >= 0; k-- {
-// 			g.Add(sum, sum, &bucket[k])
-// 			g.Add(acc, acc, sum)
-// 		}
-// 		g.Copy(&windows[m], acc)
-// 	}
-// 	g.Copy(acc, zero)
-// 	for i := len(windows) - 1; i >= 0; i-- {
-// 		for j := 0; j < int(c); j++ {
-// 			g.Double(acc, acc)
-// 		}
-// 		g.Add(acc, acc, &windows[i])
-// 	}
-// 	g.Copy(r, acc)
-// 	return r, nil
-// }
+// ClearCofactor maps given point to the prime order subgroup by multiplying
+// it with effective cofactor of hash to curve suite.
+func (g *G1) ClearCofactor(c, p *PointG1) {
+	g.MulScalar(c, p, cofactorEffG1)
+}
+
+// MapToCurve deterministically maps a field element to a point in G1 with
+// simplified SWU map, 11-isogeny and cofactor clearing. Returned point is
+// in affine form.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-6.6.3
+func (g *G1) MapToCurve(fe *Fe) *PointG1 {
+	x, y := swuMapG1(g.f, fe)
+	isogenyMapG1(g.f, x, y)
+	p := &PointG1{*x, *y, FpOne}
+	g.ClearCofactor(p, p)
+	g.Affine(p)
+	return p
+}
+
+// HashToCurve hashes a message to a point in G1 following
+// BLS12381G1_XMD:SHA-256_SSWU_RO_ suite with given domain separation tag.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-8.8
+func (g *G1) HashToCurve(msg, dst []byte) (*PointG1, error) {
+	u, err := HashToFp(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	p := g.Add(&PointG1{}, g.MapToCurve(u[0]), g.MapToCurve(u[1]))
+	g.Affine(p)
+	return p, nil
+}
+
+// MultiExp calculates sum of points multiplied by corresponding non negative
+// scalars with bucket method.
+func (g *G1) MultiExp(r *PointG1, points []*PointG1, powers []*big.Int) (*PointG1, error) {
+	if len(points) != len(powers) {
+		return nil, fmt.Errorf("%w: point and scalar vectors should be in same length", ErrInvalidLength)
+	}
+	numBits := 0
+	for i := 0; i < len(powers); i++ {
+		if powers[i].Sign() < 0 {
+			return nil, fmt.Errorf("%w: scalars should be non negative", ErrInvalidScalar)
+		}
+		if l := powers[i].BitLen(); l > numBits {
+			numBits = l
+		}
+	}
+	c := 3
+	if len(powers) > 32 {
+		c = int(math.Ceil(math.Log(float64(len(powers)))))
+	}
+	bucketSize := (1 << uint(c)) - 1
+	bucket := make([]PointG1, bucketSize)
+	acc, sum := g.Zero(), g.Zero()
+	windows := make([]PointG1, 0, numBits/c+1)
+	for i := 0; i < numBits; i += c {
+		for j := 0; j < bucketSize; j++ {
+			g.Copy(&bucket[j], g.Zero())
+		}
+		for j := 0; j < len(powers); j++ {
+			if index := window(powers[j], i, c); index != 0 {
+				g.Add(&bucket[index-1], &bucket[index-1], points[j])
+			}
+		}
+		g.Copy(acc, g.Zero())
+		g.Copy(sum, g.Zero())
+		for k := bucketSize - 1; k >= 0; k-- {
+			g.Add(sum, sum, &bucket[k])
+			g.Add(acc, acc, sum)
+		}
+		windows = append(windows, *acc)
+	}
+	g.Copy(acc, g.Zero())
+	for i := len(windows) - 1; i >= 0; i-- {
+		for j := 0; j < c; j++ {
+			g.Double(acc, acc)
+		}
+		g.Add(acc, acc, &windows[i])
+	}
+	return g.Copy(r, acc), nil
+}
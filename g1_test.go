@@ -1,17 +1,23 @@
 package bls
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
+	"io/ioutil"
 	"math/big"
 	"testing"
 )
 
 func TestG1(t *testing.T) {
 	g1 := NewG1(NewFp())
-	one := g1.fromRawUnchecked(bytes_(48,
+	one, err := g1.FromUncompressedUnchecked(bytes_(48,
 		"0x17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
 		"0x08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG1 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -105,12 +111,277 @@ func TestG1(t *testing.T) {
 	})
 }
 
+func TestG1MultiExp(t *testing.T) {
+	g1 := NewG1(NewFp())
+	for _, size := range []int{1, 5, 40} {
+		points := make([]*PointG1, size)
+		scalars := make([]*big.Int, size)
+		expected, tmp := g1.Zero(), &PointG1{}
+		for i := 0; i < size; i++ {
+			points[i] = g1.MulScalar(&PointG1{}, &G1One, randScalar(q))
+			scalars[i] = randScalar(q)
+			if i == 0 {
+				scalars[i].SetInt64(0)
+			}
+			g1.MulScalar(tmp, points[i], scalars[i])
+			g1.Add(expected, expected, tmp)
+		}
+		result := &PointG1{}
+		if _, err := g1.MultiExp(result, points, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(expected, result) {
+			t.Fatalf("bad multi exponentiation")
+		}
+	}
+	if _, err := g1.MultiExp(&PointG1{}, []*PointG1{g1.Zero()}, nil); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("vectors with different lengths should fail")
+	}
+	if _, err := g1.MultiExp(&PointG1{}, []*PointG1{g1.Zero()}, []*big.Int{big.NewInt(-1)}); !errors.Is(err, ErrInvalidScalar) {
+		t.Fatalf("negative scalar should fail")
+	}
+}
+
+func TestG1UncompressedVectors(t *testing.T) {
+	data, err := ioutil.ReadFile("tests/g1_uncompressed_valid_test_vectors.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1 := NewG1(NewFp())
+	p1 := g1.Zero()
+	for i := 0; i < 1000; i++ {
+		vector := data[i*96 : (i+1)*96]
+		p2, err := g1.FromUncompressed(vector)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !bytes.Equal(vector, g1.ToUncompressed(p2)) || !g1.Equal(p1, p2) {
+			t.Fatalf("bad encoding %d", i)
+		}
+		g1.Add(p1, p1, &G1One)
+	}
+}
+
+func TestG1UncompressedFlags(t *testing.T) {
+	g1 := NewG1(NewFp())
+	zero := make([]byte, 96)
+	point := g1.ToUncompressed(new(PointG1).Set(&G1One))
+	for _, v := range []struct {
+		flags    byte
+		in       []byte
+		infinity bool
+		ok       bool
+	}{
+		{0x00, zero, false, false},
+		{0x00, point, false, true},
+		{0x20, zero, false, false},
+		{0x20, point, false, false},
+		{0x40, zero, true, true},
+		{0x40, point, false, false},
+		{0x60, zero, false, false},
+		{0x60, point, false, false},
+		{0x80, zero, false, false},
+		{0x80, point, false, false},
+		{0xa0, zero, false, false},
+		{0xa0, point, false, false},
+		{0xc0, zero, false, false},
+		{0xc0, point, false, false},
+		{0xe0, zero, false, false},
+		{0xe0, point, false, false},
+	} {
+		in := make([]byte, 96)
+		copy(in, v.in)
+		in[0] |= v.flags
+		p, err := g1.FromUncompressed(in)
+		if (err == nil) != v.ok {
+			t.Fatalf("flags %#x: unexpected result %v", v.flags, err)
+		}
+		if err != nil && !errors.Is(err, ErrNonCanonical) && !errors.Is(err, ErrNotOnCurve) {
+			t.Fatalf("flags %#x: unexpected error %v", v.flags, err)
+		}
+		if !v.ok {
+			continue
+		}
+		if g1.IsZero(p) != v.infinity {
+			t.Fatalf("flags %#x: bad infinity decoding", v.flags)
+		}
+		if !bytes.Equal(g1.ToUncompressed(p), in) {
+			t.Fatalf("flags %#x: bad encoding", v.flags)
+		}
+	}
+}
+
+// invalidCompressedG1 returns compressed encodings of an x coordinate which
+// is not on curve and a point which is on curve but not in correct subgroup.
+func invalidCompressedG1(g1 *G1) (offCurve, offSubgroup []byte) {
+	for i := uint64(1); offCurve == nil || offSubgroup == nil; i++ {
+		x, y := new(Fe).SetUint(i), &Fe{}
+		in := x.Bytes()
+		in[0] |= 1 << 7
+		g1.f.Mont(x, x)
+		g1.f.Square(y, x)
+		g1.f.Mul(y, y, x)
+		g1.f.Add(y, y, b)
+		if !g1.f.IsSquare(y) {
+			offCurve = in
+		} else {
+			offSubgroup = in
+		}
+	}
+	return offCurve, offSubgroup
+}
+
+func TestG1CompressedStrict(t *testing.T) {
+	g1 := NewG1(NewFp())
+	offCurve, offSubgroup := invalidCompressedG1(g1)
+	valid := g1.ToCompressed(new(PointG1).Set(&G1One))
+	infinity := g1.ToCompressed(g1.Zero())
+	infinitySigned := append([]byte{}, infinity...)
+	infinitySigned[0] |= 1 << 5
+	infinityDirty := append([]byte{}, infinity...)
+	infinityDirty[47] = 1
+	overflow := modulus.Bytes()
+	overflow[0] |= 1 << 7
+	uncompressed := append([]byte{}, valid...)
+	uncompressed[0] &= 0x7f
+	for i, v := range []struct {
+		in  []byte
+		err error
+	}{
+		{valid, nil},
+		{infinity, nil},
+		{valid[:47], ErrInvalidLength},
+		{append(append([]byte{}, valid...), 0), ErrInvalidLength},
+		{[]byte{}, ErrInvalidLength},
+		{infinitySigned, ErrNonCanonical},
+		{infinityDirty, ErrNonCanonical},
+		{overflow, ErrNonCanonical},
+		{uncompressed, ErrNonCanonical},
+		{offCurve, ErrNotOnCurve},
+		{offSubgroup, ErrNotInSubgroup},
+	} {
+		if _, err := g1.FromCompressedStrict(v.in); err != v.err {
+			t.Fatalf("case %d: expected %v, got %v", i, v.err, err)
+		}
+	}
+	// lenient decoding ignores trailing bytes
+	if _, err := g1.FromCompressed(append(append([]byte{}, valid...), 0)); err != nil {
+		t.Fatal(err)
+	}
+	// unchecked decoding skips subgroup check only
+	p, err := g1.FromCompressedUnchecked(offSubgroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.IsOnCurve(p) || g1.isTorsionFree(p) {
+		t.Fatalf("bad unchecked decoding")
+	}
+	if _, err := g1.FromCompressedUnchecked(offCurve); err != ErrNotOnCurve {
+		t.Fatalf("expected %v, got %v", ErrNotOnCurve, err)
+	}
+	if _, err := g1.FromUncompressed(g1.ToUncompressed(p)); err != ErrNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrNotInSubgroup, err)
+	}
+	q, err := g1.FromUncompressedUnchecked(g1.ToUncompressed(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.Equal(p, q) {
+		t.Fatalf("bad unchecked decoding")
+	}
+}
+
+func TestG1MontEncoding(t *testing.T) {
+	g1 := NewG1(NewFp())
+	for i := 0; i < n; i++ {
+		a := g1.MulScalar(&PointG1{}, &G1One, randScalar(q))
+		b, err := g1.FromMontUnchecked(g1.ToMont(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(a, b) {
+			t.Fatalf("bad montgomery encoding")
+		}
+	}
+	b, err := g1.FromMontUnchecked(g1.ToMont(g1.Zero()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.IsZero(b) {
+		t.Fatalf("bad montgomery encoding of infinity")
+	}
+	in := make([]byte, 96)
+	copy(in, modulus.Bytes())
+	if _, err := g1.FromMontUnchecked(in); err != ErrNonCanonical {
+		t.Fatalf("expected %v, got %v", ErrNonCanonical, err)
+	}
+	if _, err := g1.FromMontUnchecked(in[1:]); err != ErrInvalidLength {
+		t.Fatalf("expected %v, got %v", ErrInvalidLength, err)
+	}
+}
+
+func TestG1MapToCurve(t *testing.T) {
+	f := NewFp()
+	g1 := NewG1(f)
+	u := &Fe{}
+	if err := f.NewElementFromBytes(u, bytes_(48,
+		"0x14406e5bfb9209256a3820879a29ac2f62d6aca82324bf3ae2aa7d3c54792043bd8c791fccdb080c1a52dc68b8b69350",
+	)); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := g1.FromUncompressed(bytes_(48,
+		"0x0d7721bcdb7ce1047557776eb2659a444166dc6dd55c7ca6e240e21ae9aa18f529f04ac31d861b54faf3307692545db7",
+		"0x108286acbdf4384f67659a8abe89e712a504cb3ce1cba07a716869025d60d499a00d1da8cdc92958918c222ea93d87f0",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := g1.MapToCurve(u); !g1.Equal(p, expected) {
+		t.Fatalf("bad mapping")
+	}
+	for i := 0; i < n; i++ {
+		if _, err := f.RandElement(u, rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		p := g1.MapToCurve(u)
+		if !g1.IsOnCurve(p) || !g1.InCorrectSubgroup(p) {
+			t.Fatalf("mapped point is not in G1")
+		}
+		if !g1.Equal(p, g1.MapToCurve(u)) {
+			t.Fatalf("mapping is not deterministic")
+		}
+	}
+}
+
+func TestG1CompressedVectors(t *testing.T) {
+	data, err := ioutil.ReadFile("tests/g1_compressed_valid_test_vectors.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1 := NewG1(NewFp())
+	p1 := g1.Zero()
+	for i := 0; i < 1000; i++ {
+		vector := data[i*48 : (i+1)*48]
+		p2, err := g1.FromCompressed(vector)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !bytes.Equal(vector, g1.ToCompressed(p2)) || !g1.Equal(p1, p2) {
+			t.Fatalf("bad compression %d", i)
+		}
+		g1.Add(p1, p1, &G1One)
+	}
+}
+
 func BenchmarkG1Add(t *testing.B) {
 	g1 := NewG1(NewFp())
-	one := g1.fromRawUnchecked(bytes_(48,
+	one, err := g1.FromUncompressedUnchecked(bytes_(48,
 		"0x17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
 		"0x08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG1 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -127,10 +398,13 @@ func BenchmarkG1Add(t *testing.B) {
 
 func BenchmarkG1Mul(t *testing.B) {
 	g1 := NewG1(NewFp())
-	one := g1.fromRawUnchecked(bytes_(48,
+	one, err := g1.FromUncompressedUnchecked(bytes_(48,
 		"0x17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
 		"0x08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG1 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -144,3 +418,24 @@ func BenchmarkG1Mul(t *testing.B) {
 		g1.MulScalar(&c, a, e)
 	}
 }
+
+func BenchmarkG1Decode(t *testing.B) {
+	g1 := NewG1(NewFp())
+	a := g1.MulScalar(&PointG1{}, &G1One, randScalar(q))
+	compressed, mont := g1.ToCompressed(a), g1.ToMont(a)
+	t.Run("Compressed", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g1.FromCompressed(compressed)
+		}
+	})
+	t.Run("CompressedUnchecked", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g1.FromCompressedUnchecked(compressed)
+		}
+	})
+	t.Run("MontUnchecked", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			g1.FromMontUnchecked(mont)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package bls
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 )
 
@@ -41,39 +42,54 @@ func NewG2(f *Fp2) *G2 {
 
 func (g *G2) FromUncompressed(uncompressed []byte) (*PointG2, error) {
 	if len(uncompressed) < 192 {
-		return nil, fmt.Errorf("input string should be equal or larger than 192")
+		return nil, fmt.Errorf("%w: input string should be equal or larger than 192", ErrInvalidLength)
+	}
+	p, err := g.FromUncompressedUnchecked(uncompressed[:192])
+	if err != nil {
+		return nil, err
+	}
+	if !g.IsOnCurve(p) {
+		return nil, ErrNotOnCurve
+	}
+	if !g.isTorsionFree(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return p, nil
+}
+
+// FromUncompressedUnchecked decodes a point from exactly 192 bytes without
+// checking whether it is on curve and in correct subgroup. It must be used
+// only for points which are known to be valid, such as those previously
+// encoded by this node, and never for untrusted input.
+func (g *G2) FromUncompressedUnchecked(uncompressed []byte) (*PointG2, error) {
+	if len(uncompressed) != 192 {
+		return nil, ErrInvalidLength
 	}
 	var in [192]byte
-	copy(in[:], uncompressed[:192])
+	copy(in[:], uncompressed)
 	if in[0]&(1<<7) != 0 {
-		return nil, fmt.Errorf("compression flag should be zero")
+		return nil, fmt.Errorf("%w: compression flag should be zero", ErrNonCanonical)
 	}
 	if in[0]&(1<<5) != 0 {
-		return nil, fmt.Errorf("sort flag should be zero")
+		return nil, fmt.Errorf("%w: sort flag should be zero", ErrNonCanonical)
 	}
-	in[0] &= 0x1f
-	x, y := &Fe2{}, &Fe2{}
-	if err := g.f.NewElementFromBytes(x, in[:96]); err != nil {
-		return nil, err
-	}
-	if err := g.f.NewElementFromBytes(y, in[96:]); err != nil {
-		return nil, err
-	}
-	if in[0]&(1<<6) == 1 {
-		if !(g.f.IsZero(x) && g.f.IsZero(y)) {
-			return nil, fmt.Errorf("input string should be zero when infinity flag is set")
+	if in[0]&(1<<6) != 0 {
+		in[0] &= 0x3f
+		for i := 0; i < 192; i++ {
+			if in[i] != 0 {
+				return nil, fmt.Errorf("%w: input string should be zero when infinity flag is set", ErrNonCanonical)
+			}
 		}
+		return g.Zero(), nil
 	}
 	p := &PointG2{}
-	g.f.Copy(&p[0], x)
-	g.f.Copy(&p[1], y)
-	g.f.Copy(&p[2], &Fp2One)
-	if !g.IsOnCurve(p) {
-		return nil, fmt.Errorf("point is not on curve")
+	if err := g.f.NewElementFromBytes(&p[0], in[:96]); err != nil {
+		return nil, err
 	}
-	if !g.isTorsionFree(p) {
-		return nil, fmt.Errorf("point is not on correct subgroup")
+	if err := g.f.NewElementFromBytes(&p[1], in[96:]); err != nil {
+		return nil, err
 	}
+	g.f.Copy(&p[2], &Fp2One)
 	return p, nil
 }
 
@@ -82,6 +98,7 @@ func (g *G2) ToUncompressed(p *PointG2) []byte {
 	g.Affine(p)
 	if g.IsZero(p) {
 		out[0] |= 1 << 6
+		return out
 	}
 	copy(out[:96], g.f.ToBytes(&p[0]))
 	copy(out[96:], g.f.ToBytes(&p[1]))
@@ -90,52 +107,72 @@ func (g *G2) ToUncompressed(p *PointG2) []byte {
 
 func (g *G2) FromCompressed(compressed []byte) (*PointG2, error) {
 	if len(compressed) < 96 {
-		return nil, fmt.Errorf("input string should be equal or larger than 96")
+		return nil, ErrInvalidLength
+	}
+	return g.FromCompressedStrict(compressed[:96])
+}
+
+// FromCompressedStrict decodes a point from exactly 96 bytes. Encodings
+// with a coordinate larger than modulus or with infinity and sign flags set
+// together are rejected with ErrNonCanonical.
+func (g *G2) FromCompressedStrict(in []byte) (*PointG2, error) {
+	p, err := g.FromCompressedUnchecked(in)
+	if err != nil {
+		return nil, err
+	}
+	if !g.isTorsionFree(p) {
+		return nil, ErrNotInSubgroup
+	}
+	return p, nil
+}
+
+// FromCompressedUnchecked is FromCompressedStrict without subgroup check.
+// It must not be used for untrusted input.
+func (g *G2) FromCompressedUnchecked(in []byte) (*PointG2, error) {
+	if len(in) != 96 {
+		return nil, ErrInvalidLength
 	}
-	var in [96]byte
-	copy(in[:], compressed[:])
 	if in[0]&(1<<7) == 0 {
-		return nil, fmt.Errorf("bad compression")
+		return nil, ErrNonCanonical
 	}
+	a := in[0]&(1<<5) != 0
 	if in[0]&(1<<6) != 0 {
-		in[0] &= 0x3f
-		for i := 0; i < 48; i++ {
+		if a || in[0]&0x1f != 0 {
+			return nil, ErrNonCanonical
+		}
+		for i := 1; i < 96; i++ {
 			if in[i] != 0 {
-				return nil, fmt.Errorf("bad infinity compression")
+				return nil, ErrNonCanonical
 			}
 		}
 		return g.Zero(), nil
 	}
-	a := in[0]&(1<<5) != 0
-	in[0] &= 0x1f
+	var buf [96]byte
+	copy(buf[:], in)
+	buf[0] &= 0x1f
 	x := &Fe2{}
-	if err := g.f.NewElementFromBytes(x, in[:]); err != nil {
-		return nil, err
+	x[1].FromBytes(buf[:48])
+	x[0].FromBytes(buf[48:])
+	if !g.f.f.Valid(&x[0]) || !g.f.f.Valid(&x[1]) {
+		return nil, ErrNonCanonical
 	}
+	g.f.f.Mont(&x[0], &x[0])
+	g.f.f.Mont(&x[1], &x[1])
 	// solve curve equation
 	y := &Fe2{}
 	g.f.Square(y, x)
 	g.f.Mul(y, y, x)
 	g.f.Add(y, y, b2)
 	if ok := g.f.Sqrt(y, y); !ok {
-		return nil, fmt.Errorf("point is not on curve")
+		return nil, ErrNotOnCurve
 	}
-	// select lexicographically, should be in mont reduced form
-	negYn, negY, yn := &Fe2{}, &Fe2{}, &Fe2{}
-	g.f.f.Demont(&yn[0], &y[0])
-	g.f.f.Demont(&yn[1], &y[1])
-	g.f.Neg(negY, y)
-	g.f.Neg(negYn, yn)
-	if (yn[1].Cmp(&negYn[1]) > 0 != a) || (yn[1].IsZero() && yn[0].Cmp(&negYn[0]) > 0 != a) {
-		g.f.Copy(y, negY)
+	if g.f.LexicographicallyLargest(y) != a {
+		g.f.Neg(y, y)
 	}
 	p := &PointG2{}
 	g.f.Copy(&p[0], x)
 	g.f.Copy(&p[1], y)
 	g.f.Copy(&p[2], &Fp2One)
-	if !g.isTorsionFree(p) {
-		return nil, fmt.Errorf("point is not on correct subgroup")
-	}
 	return p, nil
 }
 
@@ -146,12 +183,7 @@ func (g *G2) ToCompressed(p *PointG2) []byte {
 		out[0] |= 1 << 6
 	} else {
 		copy(out[:], g.f.ToBytes(&p[0]))
-		y, negY := &Fe2{}, &Fe2{}
-		g.f.Copy(y, &p[1])
-		g.f.f.Demont(&y[0], &y[0])
-		g.f.f.Demont(&y[1], &y[1])
-		g.f.Neg(negY, y)
-		if (y[1].Cmp(&negY[1]) > 0) || (y[1].IsZero() && y[1].Cmp(&negY[1]) > 0) {
+		if g.f.LexicographicallyLargest(&p[1]) {
 			out[0] |= 1 << 5
 		}
 	}
@@ -159,16 +191,58 @@ func (g *G2) ToCompressed(p *PointG2) []byte {
 	return out
 }
 
-func (g *G2) fromRawUnchecked(in []byte) *PointG2 {
-	p := &PointG2{}
-	if err := g.f.NewElementFromBytes(&p[0], in[:96]); err != nil {
-		panic(err)
+// ToMont encodes affine coordinates of the point in Montgomery form so that
+// FromMontUnchecked can load it back without any field conversion. Point at
+// infinity is encoded as in uncompressed form. This encoding is internal to
+// this implementation and should only be used for local storage.
+func (g *G2) ToMont(p *PointG2) []byte {
+	out := make([]byte, 192)
+	g.Affine(p)
+	if g.IsZero(p) {
+		out[0] |= 1 << 6
+		return out
 	}
-	if err := g.f.NewElementFromBytes(&p[1], in[96:]); err != nil {
-		panic(err)
+	copy(out[:48], p[0][1].Bytes())
+	copy(out[48:96], p[0][0].Bytes())
+	copy(out[96:144], p[1][1].Bytes())
+	copy(out[144:], p[1][0].Bytes())
+	return out
+}
+
+// FromMontUnchecked decodes a point encoded with ToMont. Only the range of
+// coordinates is checked, it must not be used for untrusted input.
+func (g *G2) FromMontUnchecked(in []byte) (*PointG2, error) {
+	if len(in) != 192 {
+		return nil, ErrInvalidLength
+	}
+	if in[0]&(1<<6) != 0 {
+		for i := 1; i < 192; i++ {
+			if in[i] != 0 {
+				return nil, ErrNonCanonical
+			}
+		}
+		if in[0] != 1<<6 {
+			return nil, ErrNonCanonical
+		}
+		return g.Zero(), nil
+	}
+	p := &PointG2{}
+	p[0][1].FromBytes(in[:48])
+	p[0][0].FromBytes(in[48:96])
+	p[1][1].FromBytes(in[96:144])
+	p[1][0].FromBytes(in[144:])
+	for i := 0; i < 2; i++ {
+		if !g.f.f.Valid(&p[i][0]) || !g.f.f.Valid(&p[i][1]) {
+			return nil, ErrNonCanonical
+		}
 	}
 	g.f.Copy(&p[2], &Fp2One)
-	return p
+	return p, nil
+}
+
+// InCorrectSubgroup checks whether given point is in the prime order subgroup.
+func (g *G2) InCorrectSubgroup(p *PointG2) bool {
+	return g.isTorsionFree(p)
 }
 
 func (g *G2) isTorsionFree(p *PointG2) bool {
@@ -365,13 +439,34 @@ func (g *G2) MulByCofactor(c, p *PointG2) {
 	g.MulScalar(c, p, cofactorG2)
 }
 
-func (g *G2) MapToPoint(in []byte) *PointG2 {
+// ClearCofactor maps given point to the prime order subgroup by multiplying
+// it with effective cofactor of hash to curve suite.
+func (g *G2) ClearCofactor(c, p *PointG2) {
+	g.MulScalar(c, p, cofactorEffG2)
+}
+
+// MapToCurve deterministically maps a field element to a point in G2 with
+// simplified SWU map, 3-isogeny and cofactor clearing. Returned point is in
+// affine form.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-6.6.3
+func (g *G2) MapToCurve(fe *Fe2) *PointG2 {
+	x, y := swuMapG2(g.f, fe)
+	isogenyMapG2(g.f, x, y)
+	p := &PointG2{*x, *y, Fp2One}
+	g.ClearCofactor(p, p)
+	g.Affine(p)
+	return p
+}
+
+// MapToPoint maps bytes to a point in G2 with try and increment method. It
+// is not constant time and not compatible with hash to curve suites.
+//
+// Deprecated: use MapToCurve.
+func (g *G2) MapToPoint(in []byte) (*PointG2, error) {
 	x, y := &Fe2{}, &Fe2{}
 	fp2 := g.f
-	fp := fp2.f
-	err := fp2.NewElementFromBytes(x, in)
-	if err != nil {
-		panic(err)
+	if err := fp2.NewElementFromBytes(x, in); err != nil {
+		return nil, err
 	}
 	for {
 		fp2.Square(y, x)
@@ -379,64 +474,76 @@ func (g *G2) MapToPoint(in []byte) *PointG2 {
 		fp2.Add(y, y, b2)
 		if ok := fp2.Sqrt(y, y); ok {
 			// favour negative y
-			negYn, negY, yn := &Fe2{}, &Fe2{}, &Fe2{}
-			fp.Demont(&yn[0], &y[0])
-			fp.Demont(&yn[1], &y[1])
-			fp2.Neg(negY, y)
-			fp2.Neg(negYn, yn)
-			if yn[1].Cmp(&negYn[1]) > 0 || (yn[1].IsZero() && yn[0].Cmp(&negYn[0]) > 0) {
-				fp2.Copy(y, y)
-			} else {
-				fp2.Copy(y, negY)
+			if !fp2.LexicographicallyLargest(y) {
+				fp2.Neg(y, y)
 			}
 			p := &PointG2{*x, *y, Fp2One}
 			g.MulByCofactor(p, p)
-			return p
+			return p, nil
 		}
 		fp2.Add(x, x, &Fp2One)
 	}
 }
 
-// func (g *G2) MultiExp(r *PointG2, points []*PointG2, powers []*big.Int) (*PointG2, error) {
-// 	if len(points) != len(powers) {
-// 		return nil, fmt.Errorf("point and scalar vectors should be in same length")
-// 	}
-// 	var c uint = 3
-// 	if len(powers) > 32 {
-// 		c = uint(math.Ceil(math.Log10(float64(len(powers)))))
-// 	}
-// 	bucket_size, numBits := (1<<c)-1, q.BitLen()
-// 	windows := make([]PointG2, numBits/int(c)+1)
-// 	bucket := make([]PointG2, bucket_size)
-// 	acc, sum, zero := g.Zero(), g.Zero(), g.Zero()
-// 	s := new(big.Int)
-// 	for i, m := 0, 0; i <= numBits; i, m = i+int(c), m+1 {
-// 		for i := 0; i < bucket_size; i++ {
-// 			g.Copy(&bucket[i], zero)
-// 		}
-// 		for j := 0; j < len(powers); j++ {
-// 			s = powers[j]
-// 			index := s.Uint64() & uint64(bucket_size)
-// 			if index != 0 {
-// 				g.Add(&bucket[index-1], &bucket[index-1], points[j])
-// 			}
-// 			s.Rsh(s, c)
-// 		}
-// 		g.Copy(acc, zero)
-// 		g.Copy(sum, zero)
-// 		for k := bucket_size - 1; k >= 0; k-- {
-// 			g.Add(sum, sum, &bucket[k])
-// 			g.Add(acc, acc, sum)
-// 		}
-// 		g.Copy(&windows[m], acc)
-// 	}
-// 	g.Copy(acc, zero)
-// 	for i := len(windows) - 1; i >= 0; i-- {
-// 		for j := 0; j < int(c); j++ {
-// 			g.Double(acc, acc)
-// 		}
-// 		g.Add(acc, acc, &windows[i])
-// 	}
-// 	g.Copy(r, acc)
-// 	return r, nil
-// }
+// HashToCurve hashes a message to a point in G2 following
+// BLS12381G2_XMD:SHA-256_SSWU_RO_ suite with given domain separation tag.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-8.8
+func (g *G2) HashToCurve(msg, dst []byte) (*PointG2, error) {
+	u, err := HashToFp2(msg, dst, 2)
+	if err != nil {
+		return nil, err
+	}
+	p := g.Add(&PointG2{}, g.MapToCurve(u[0]), g.MapToCurve(u[1]))
+	g.Affine(p)
+	return p, nil
+}
+
+// MultiExp calculates sum of points multiplied by corresponding non negative
+// scalars with bucket method.
+func (g *G2) MultiExp(r *PointG2, points []*PointG2, powers []*big.Int) (*PointG2, error) {
+	if len(points) != len(powers) {
+		return nil, fmt.Errorf("%w: point and scalar vectors should be in same length", ErrInvalidLength)
+	}
+	numBits := 0
+	for i := 0; i < len(powers); i++ {
+		if powers[i].Sign() < 0 {
+			return nil, fmt.Errorf("%w: scalars should be non negative", ErrInvalidScalar)
+		}
+		if l := powers[i].BitLen(); l > numBits {
+			numBits = l
+		}
+	}
+	c := 3
+	if len(powers) > 32 {
+		c = int(math.Ceil(math.Log(float64(len(powers)))))
+	}
+	bucketSize := (1 << uint(c)) - 1
+	bucket := make([]PointG2, bucketSize)
+	acc, sum := g.Zero(), g.Zero()
+	windows := make([]PointG2, 0, numBits/c+1)
+	for i := 0; i < numBits; i += c {
+		for j := 0; j < bucketSize; j++ {
+			g.Copy(&bucket[j], g.Zero())
+		}
+		for j := 0; j < len(powers); j++ {
+			if index := window(powers[j], i, c); index != 0 {
+				g.Add(&bucket[index-1], &bucket[index-1], points[j])
+			}
+		}
+		g.Copy(acc, g.Zero())
+		g.Copy(sum, g.Zero())
+		for k := bucketSize - 1; k >= 0; k-- {
+			g.Add(sum, sum, &bucket[k])
+			g.Add(acc, acc, sum)
+		}
+		windows = append(windows, *acc)
+	}
+	g.Copy(acc, g.Zero())
+	for i := len(windows) - 1; i >= 0; i-- {
+		for j := 0; j < c; j++ {
+			g.Double(acc, acc)
+		}
+		g.Add(acc, acc, &windows[i])
+	}
+	return g.Copy(r, acc), nil
+}
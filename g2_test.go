@@ -1,19 +1,25 @@
 package bls
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
+	"io/ioutil"
 	"math/big"
 	"testing"
 )
 
 func TestG2(t *testing.T) {
 	g2 := NewG2(NewFp2(NewFp()))
-	one := g2.fromRawUnchecked(bytes_(48,
+	one, err := g2.FromUncompressedUnchecked(bytes_(48,
 		"0x13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e",
 		"0x024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
 		"0x0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be",
 		"0x0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG2 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -107,14 +113,307 @@ func TestG2(t *testing.T) {
 	})
 }
 
+func TestG2MultiExp(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	for _, size := range []int{1, 5, 40} {
+		points := make([]*PointG2, size)
+		scalars := make([]*big.Int, size)
+		expected, tmp := g2.Zero(), &PointG2{}
+		for i := 0; i < size; i++ {
+			points[i] = g2.MulScalar(&PointG2{}, &G2One, randScalar(q))
+			scalars[i] = randScalar(q)
+			if i == 0 {
+				scalars[i].SetInt64(0)
+			}
+			g2.MulScalar(tmp, points[i], scalars[i])
+			g2.Add(expected, expected, tmp)
+		}
+		result := &PointG2{}
+		if _, err := g2.MultiExp(result, points, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(expected, result) {
+			t.Fatalf("bad multi exponentiation")
+		}
+	}
+	if _, err := g2.MultiExp(&PointG2{}, []*PointG2{g2.Zero()}, nil); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("vectors with different lengths should fail")
+	}
+	if _, err := g2.MultiExp(&PointG2{}, []*PointG2{g2.Zero()}, []*big.Int{big.NewInt(-1)}); !errors.Is(err, ErrInvalidScalar) {
+		t.Fatalf("negative scalar should fail")
+	}
+}
+
+func TestG2UncompressedVectors(t *testing.T) {
+	data, err := ioutil.ReadFile("tests/g2_uncompressed_valid_test_vectors.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2 := NewG2(NewFp2(NewFp()))
+	p1 := g2.Zero()
+	for i := 0; i < 1000; i++ {
+		vector := data[i*192 : (i+1)*192]
+		p2, err := g2.FromUncompressed(vector)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !bytes.Equal(vector, g2.ToUncompressed(p2)) || !g2.Equal(p1, p2) {
+			t.Fatalf("bad encoding %d", i)
+		}
+		g2.Add(p1, p1, &G2One)
+	}
+}
+
+func TestG2UncompressedFlags(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	zero := make([]byte, 192)
+	point := g2.ToUncompressed(new(PointG2).Set(&G2One))
+	for _, v := range []struct {
+		flags    byte
+		in       []byte
+		infinity bool
+		ok       bool
+	}{
+		{0x00, zero, false, false},
+		{0x00, point, false, true},
+		{0x20, zero, false, false},
+		{0x20, point, false, false},
+		{0x40, zero, true, true},
+		{0x40, point, false, false},
+		{0x60, zero, false, false},
+		{0x60, point, false, false},
+		{0x80, zero, false, false},
+		{0x80, point, false, false},
+		{0xa0, zero, false, false},
+		{0xa0, point, false, false},
+		{0xc0, zero, false, false},
+		{0xc0, point, false, false},
+		{0xe0, zero, false, false},
+		{0xe0, point, false, false},
+	} {
+		in := make([]byte, 192)
+		copy(in, v.in)
+		in[0] |= v.flags
+		p, err := g2.FromUncompressed(in)
+		if (err == nil) != v.ok {
+			t.Fatalf("flags %#x: unexpected result %v", v.flags, err)
+		}
+		if err != nil && !errors.Is(err, ErrNonCanonical) && !errors.Is(err, ErrNotOnCurve) {
+			t.Fatalf("flags %#x: unexpected error %v", v.flags, err)
+		}
+		if !v.ok {
+			continue
+		}
+		if g2.IsZero(p) != v.infinity {
+			t.Fatalf("flags %#x: bad infinity decoding", v.flags)
+		}
+		if !bytes.Equal(g2.ToUncompressed(p), in) {
+			t.Fatalf("flags %#x: bad encoding", v.flags)
+		}
+	}
+}
+
+// invalidCompressedG2 returns compressed encodings of an x coordinate which
+// is not on curve and a point which is on curve but not in correct subgroup.
+func invalidCompressedG2(g2 *G2) (offCurve, offSubgroup []byte) {
+	for i := uint64(1); offCurve == nil || offSubgroup == nil; i++ {
+		x, y := &Fe2{}, &Fe2{}
+		x[0].SetUint(i)
+		x[1].SetUint(1)
+		g2.f.f.Mont(&x[0], &x[0])
+		g2.f.f.Mont(&x[1], &x[1])
+		in := g2.f.ToBytes(x)
+		in[0] |= 1 << 7
+		g2.f.Square(y, x)
+		g2.f.Mul(y, y, x)
+		g2.f.Add(y, y, b2)
+		if !g2.f.IsSquare(y) {
+			offCurve = in
+		} else {
+			offSubgroup = in
+		}
+	}
+	return offCurve, offSubgroup
+}
+
+func TestG2CompressedStrict(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	offCurve, offSubgroup := invalidCompressedG2(g2)
+	valid := g2.ToCompressed(new(PointG2).Set(&G2One))
+	infinity := g2.ToCompressed(g2.Zero())
+	infinitySigned := append([]byte{}, infinity...)
+	infinitySigned[0] |= 1 << 5
+	infinityDirty := append([]byte{}, infinity...)
+	infinityDirty[95] = 1
+	overflow := append([]byte{}, valid...)
+	copy(overflow[48:], modulus.Bytes())
+	uncompressed := append([]byte{}, valid...)
+	uncompressed[0] &= 0x7f
+	for i, v := range []struct {
+		in  []byte
+		err error
+	}{
+		{valid, nil},
+		{infinity, nil},
+		{valid[:95], ErrInvalidLength},
+		{append(append([]byte{}, valid...), 0), ErrInvalidLength},
+		{[]byte{}, ErrInvalidLength},
+		{infinitySigned, ErrNonCanonical},
+		{infinityDirty, ErrNonCanonical},
+		{overflow, ErrNonCanonical},
+		{uncompressed, ErrNonCanonical},
+		{offCurve, ErrNotOnCurve},
+		{offSubgroup, ErrNotInSubgroup},
+	} {
+		if _, err := g2.FromCompressedStrict(v.in); err != v.err {
+			t.Fatalf("case %d: expected %v, got %v", i, v.err, err)
+		}
+	}
+	// lenient decoding ignores trailing bytes
+	if _, err := g2.FromCompressed(append(append([]byte{}, valid...), 0)); err != nil {
+		t.Fatal(err)
+	}
+	// unchecked decoding skips subgroup check only
+	p, err := g2.FromCompressedUnchecked(offSubgroup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.IsOnCurve(p) || g2.isTorsionFree(p) {
+		t.Fatalf("bad unchecked decoding")
+	}
+	if _, err := g2.FromCompressedUnchecked(offCurve); err != ErrNotOnCurve {
+		t.Fatalf("expected %v, got %v", ErrNotOnCurve, err)
+	}
+	if _, err := g2.FromUncompressed(g2.ToUncompressed(p)); err != ErrNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrNotInSubgroup, err)
+	}
+	q, err := g2.FromUncompressedUnchecked(g2.ToUncompressed(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.Equal(p, q) {
+		t.Fatalf("bad unchecked decoding")
+	}
+}
+
+func TestG2MontEncoding(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	for i := 0; i < n; i++ {
+		a := g2.MulScalar(&PointG2{}, &G2One, randScalar(q))
+		b, err := g2.FromMontUnchecked(g2.ToMont(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(a, b) {
+			t.Fatalf("bad montgomery encoding")
+		}
+	}
+	b, err := g2.FromMontUnchecked(g2.ToMont(g2.Zero()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.IsZero(b) {
+		t.Fatalf("bad montgomery encoding of infinity")
+	}
+	in := make([]byte, 192)
+	copy(in, modulus.Bytes())
+	if _, err := g2.FromMontUnchecked(in); err != ErrNonCanonical {
+		t.Fatalf("expected %v, got %v", ErrNonCanonical, err)
+	}
+	if _, err := g2.FromMontUnchecked(in[1:]); err != ErrInvalidLength {
+		t.Fatalf("expected %v, got %v", ErrInvalidLength, err)
+	}
+}
+
+func TestG2MapToPoint(t *testing.T) {
+	g2 := NewG2(NewFp2(NewFp()))
+	in := make([]byte, 96)
+	for i := 0; i < n; i++ {
+		if _, err := rand.Read(in); err != nil {
+			t.Fatal(err)
+		}
+		in[0], in[48] = 0, 0
+		p, err := g2.MapToPoint(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.IsOnCurve(p) || !g2.isTorsionFree(p) {
+			t.Fatalf("mapped point is not in G2")
+		}
+	}
+	if _, err := g2.MapToPoint(in[:95]); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("short input should not be mapped")
+	}
+	in[0] = 0xff
+	if _, err := g2.MapToPoint(in); !errors.Is(err, ErrNonCanonical) {
+		t.Fatalf("non canonical input should not be mapped")
+	}
+}
+
+func TestG2MapToCurve(t *testing.T) {
+	fp2 := NewFp2(NewFp())
+	g2 := NewG2(fp2)
+	u := &Fe2{}
+	if err := fp2.NewElementFromBytes(u, bytes_(48,
+		"0x0e885bb33996e12f07da69073e2c0cc880bc8eff26d2a724299eb12d54f4bcf26f4748bb020e80a7e3794a7b0e47a641",
+		"0x14406e5bfb9209256a3820879a29ac2f62d6aca82324bf3ae2aa7d3c54792043bd8c791fccdb080c1a52dc68b8b69350",
+	)); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := g2.FromUncompressed(bytes_(48,
+		"0x1993f668fb1ae603aefbb1323000033fcb3b65d8ed3bf09c84c61e27704b745f540299a1872cd697ae45a5afd780f1d6",
+		"0x0d029393d3a13ff5b26fe52bd8953768946c5510f9441f1136f1e938957882db6adbd7504177ee49281ecccba596f2bf",
+		"0x17c688ae9a8b59a7069c27f2d58dd2196cb414f4fb89da8510518a1142ab19d158badd1c3bad03408fafb1669903cd6c",
+		"0x079cb41060ef7a128d286c9ef8638689a49ca19da8672ea5c47b6ba6dbde193ee835d3b87a76a689966037c07159c10d",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := g2.MapToCurve(u); !g2.Equal(p, expected) {
+		t.Fatalf("bad mapping")
+	}
+	for i := 0; i < n; i++ {
+		if _, err := fp2.RandElement(u, rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		p := g2.MapToCurve(u)
+		if !g2.IsOnCurve(p) || !g2.InCorrectSubgroup(p) {
+			t.Fatalf("mapped point is not in G2")
+		}
+	}
+}
+
+func TestG2CompressedVectors(t *testing.T) {
+	data, err := ioutil.ReadFile("tests/g2_compressed_valid_test_vectors.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2 := NewG2(NewFp2(NewFp()))
+	p1 := g2.Zero()
+	for i := 0; i < 1000; i++ {
+		vector := data[i*96 : (i+1)*96]
+		p2, err := g2.FromCompressed(vector)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if !bytes.Equal(vector, g2.ToCompressed(p2)) || !g2.Equal(p1, p2) {
+			t.Fatalf("bad compression %d", i)
+		}
+		g2.Add(p1, p1, &G2One)
+	}
+}
+
 func BenchmarkG2Add(t *testing.B) {
 	g2 := NewG2(NewFp2(NewFp()))
-	one := g2.fromRawUnchecked(bytes_(48,
+	one, err := g2.FromUncompressedUnchecked(bytes_(48,
 		"0x13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e",
 		"0x024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
 		"0x0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be",
 		"0x0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG2 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -131,12 +430,15 @@ func BenchmarkG2Add(t *testing.B) {
 
 func BenchmarkG2Mul(t *testing.B) {
 	g2 := NewG2(NewFp2(NewFp()))
-	one := g2.fromRawUnchecked(bytes_(48,
+	one, err := g2.FromUncompressedUnchecked(bytes_(48,
 		"0x13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e",
 		"0x024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
 		"0x0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be",
 		"0x0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801",
 	))
+	if err != nil {
+		t.Fatal(err)
+	}
 	randPoint := func() *PointG2 {
 		k, err := rand.Int(rand.Reader, q)
 		if err != nil {
@@ -1,3 +1,8 @@
 module github.com/kilic/bls12-381
 
-go 1.12
+go 1.13
+
+require (
+	golang.org/x/crypto v0.0.0-20201016220609-9e8e0b390897
+	golang.org/x/text v0.3.5
+)
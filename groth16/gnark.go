@@ -0,0 +1,134 @@
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/internal/gnark"
+)
+
+// gnark writes points compressed in zcash format and slices with a big endian
+// uint32 length prefix.
+//
+// verifying key: [alpha]1 [beta]1 [beta]2 [gamma]2 [delta]1 [delta]2
+//	uint32(len(K)) [K]1 [][]uint64 committed public inputs
+//	uint32(len(commitment keys)) commitment keys
+//
+// proof: [A]1 [B]2 [C]1 uint32(len(commitments)) [commitments]1 [pok]1
+//
+// public witness: uint32(public) uint32(secret) uint32(len) [x]32
+//
+// Keys written by versions before Pedersen commitment support end after K and
+// proofs end after C. Keys and proofs with commitments are not supported.
+
+// ReadGnarkVerifyingKey decodes verifying key written by gnark's
+// VerifyingKey.WriteTo.
+func ReadGnarkVerifyingKey(r io.Reader) (*VerifyingKey, error) {
+	d := gnark.NewReader(r)
+	alpha, err := d.G1Point()
+	if err != nil {
+		return nil, fmt.Errorf("alpha: %w", err)
+	}
+	// G1 beta is not used by the verifier
+	if _, err := d.G1Point(); err != nil {
+		return nil, fmt.Errorf("beta: %w", err)
+	}
+	beta, err := d.G2Point()
+	if err != nil {
+		return nil, fmt.Errorf("beta: %w", err)
+	}
+	gamma, err := d.G2Point()
+	if err != nil {
+		return nil, fmt.Errorf("gamma: %w", err)
+	}
+	// G1 delta is not used by the verifier
+	if _, err := d.G1Point(); err != nil {
+		return nil, fmt.Errorf("delta: %w", err)
+	}
+	delta, err := d.G2Point()
+	if err != nil {
+		return nil, fmt.Errorf("delta: %w", err)
+	}
+	n, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 || n > 1<<24 {
+		return nil, fmt.Errorf("invalid number of K points %d", n)
+	}
+	ic := make([]*bls.PointG1, n)
+	for i := range ic {
+		if ic[i], err = d.G1Point(); err != nil {
+			return nil, fmt.Errorf("K point %d: %w", i, err)
+		}
+	}
+	if !d.End() {
+		if err := readNoCommitments(d); err != nil {
+			return nil, err
+		}
+		if !d.End() {
+			return nil, errors.New("unexpected trailing data")
+		}
+	}
+	return NewVerifyingKey(alpha, beta, gamma, delta, ic)
+}
+
+// readNoCommitments reads commitment sections of verifying key and fails if
+// any commitment is present.
+func readNoCommitments(d *gnark.Reader) error {
+	committed, err := d.Uint32()
+	if err != nil {
+		return err
+	}
+	keys, err := d.Uint32()
+	if err != nil {
+		return err
+	}
+	if committed != 0 || keys != 0 {
+		return fmt.Errorf("%w: Pedersen commitments", ErrUnsupported)
+	}
+	return nil
+}
+
+// ReadGnarkProof decodes proof written by gnark's Proof.WriteTo.
+func ReadGnarkProof(r io.Reader) (*Proof, error) {
+	d := gnark.NewReader(r)
+	var err error
+	proof := &Proof{}
+	if proof.A, err = d.G1Point(); err != nil {
+		return nil, fmt.Errorf("A: %w", err)
+	}
+	if proof.B, err = d.G2Point(); err != nil {
+		return nil, fmt.Errorf("B: %w", err)
+	}
+	if proof.C, err = d.G1Point(); err != nil {
+		return nil, fmt.Errorf("C: %w", err)
+	}
+	if d.End() {
+		return proof, nil
+	}
+	n, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	if n != 0 {
+		return nil, fmt.Errorf("%w: Pedersen commitments", ErrUnsupported)
+	}
+	// proof of knowledge of empty commitments is the point at infinity
+	pok, err := d.G1Point()
+	if err != nil {
+		return nil, fmt.Errorf("commitment proof of knowledge: %w", err)
+	}
+	if !bls.NewG1(bls.NewFp()).IsZero(pok) || !d.End() {
+		return nil, fmt.Errorf("%w: Pedersen commitments", ErrUnsupported)
+	}
+	return proof, nil
+}
+
+// ReadGnarkPublicWitness decodes public witness written by gnark's
+// Witness.WriteTo.
+func ReadGnarkPublicWitness(r io.Reader) ([]*bls.Fr, error) {
+	return gnark.ReadPublicWitness(r)
+}
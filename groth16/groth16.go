@@ -0,0 +1,172 @@
+// Package groth16 implements verifier of Groth16 zk-SNARKs over BLS12-381
+// with import of verifying keys and proofs produced by gnark. Import of
+// snarkjs JSON files is experimental and unverified, it is not yet tested
+// against files written by snarkjs.
+// https://eprint.iacr.org/2016/260.pdf
+package groth16
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Errors returned by verification and decoding functions.
+var (
+	ErrPublicInputs = errors.New("number of public inputs does not match verifying key")
+	ErrUnsupported  = errors.New("unsupported verifying key or proof")
+)
+
+// VerifyingKey is a Groth16 verifying key. IC holds commitments to public
+// input polynomials, the first one belongs to the constant wire.
+type VerifyingKey struct {
+	Alpha *bls.PointG1
+	Beta  *bls.PointG2
+	Gamma *bls.PointG2
+	Delta *bls.PointG2
+	IC    []*bls.PointG1
+	// precomputed e(alpha, beta), -gamma and -delta
+	alphaBeta *bls.Fe12
+	negGamma  *bls.PointG2
+	negDelta  *bls.PointG2
+}
+
+// Proof is a Groth16 proof.
+type Proof struct {
+	A *bls.PointG1
+	B *bls.PointG2
+	C *bls.PointG1
+}
+
+// NewVerifyingKey creates verifying key and precomputes the constant
+// pairing e(alpha, beta).
+func NewVerifyingKey(alpha *bls.PointG1, beta, gamma, delta *bls.PointG2, ic []*bls.PointG1) (*VerifyingKey, error) {
+	if len(ic) == 0 {
+		return nil, errors.New("verifying key should have at least one IC point")
+	}
+	e := bls.NewBLSPairingEngine()
+	vk := &VerifyingKey{
+		Alpha: alpha,
+		Beta:  beta,
+		Gamma: gamma,
+		Delta: delta,
+		IC:    ic,
+	}
+	vk.alphaBeta = pair(e, []*bls.PointG1{alpha}, []*bls.PointG2{beta})
+	vk.negGamma = e.G2.Neg(&bls.PointG2{}, gamma)
+	vk.negDelta = e.G2.Neg(&bls.PointG2{}, delta)
+	return vk, nil
+}
+
+// NumPublicInputs returns number of public inputs the key expects.
+func (vk *VerifyingKey) NumPublicInputs() int {
+	return len(vk.IC) - 1
+}
+
+// Verify checks proof against public inputs with a single multi pairing,
+// e(A, B) * e(L, -gamma) * e(C, -delta) == e(alpha, beta) where
+// L = IC_0 + sum x_i * IC_{i+1}.
+func (vk *VerifyingKey) Verify(proof *Proof, public []*bls.Fr) (bool, error) {
+	if len(public) != vk.NumPublicInputs() {
+		return false, ErrPublicInputs
+	}
+	e := bls.NewBLSPairingEngine()
+	scalars := make([]*big.Int, len(public)+1)
+	scalars[0] = big.NewInt(1)
+	for i := range public {
+		scalars[i+1] = public[i].ToBig()
+	}
+	l, err := e.G1.MultiExp(&bls.PointG1{}, vk.IC, scalars)
+	if err != nil {
+		return false, err
+	}
+	f := pair(e, []*bls.PointG1{proof.A, l, proof.C}, []*bls.PointG2{proof.B, vk.negGamma, vk.negDelta})
+	return e.Fp12.Equal(f, vk.alphaBeta), nil
+}
+
+// BatchVerify checks many proofs of the same key at once with a random
+// linear combination of verification equations. It costs a pairing per
+// proof and two more instead of three per proof. Randomness is read from r,
+// if r is nil crypto/rand is used.
+func (vk *VerifyingKey) BatchVerify(proofs []*Proof, publics [][]*bls.Fr, r io.Reader) (bool, error) {
+	k := len(proofs)
+	if len(publics) != k {
+		return false, errors.New("proofs and public inputs should be in same length")
+	}
+	if k == 0 {
+		return true, nil
+	}
+	e := bls.NewBLSPairingEngine()
+	g1 := e.G1
+	// prod e(r_j * A_j, B_j) * e(sum r_j * L_j, -gamma) * e(sum r_j * C_j, -delta)
+	// == e(alpha, beta)^(sum r_j)
+	points := make([]*bls.PointG1, 0, k+2)
+	twistPoints := make([]*bls.PointG2, 0, k+2)
+	rs := make([]*big.Int, k)
+	sum := new(bls.Fr)
+	icScalars := make([]*bls.Fr, len(vk.IC))
+	for i := range icScalars {
+		icScalars[i] = new(bls.Fr)
+	}
+	t := new(bls.Fr)
+	for j := 0; j < k; j++ {
+		if len(publics[j]) != vk.NumPublicInputs() {
+			return false, fmt.Errorf("proof %d: %w", j, ErrPublicInputs)
+		}
+		rj, err := new(bls.Fr).Rand(r)
+		if err != nil {
+			return false, err
+		}
+		rs[j] = rj.ToBig()
+		sum.Add(sum, rj)
+		icScalars[0].Add(icScalars[0], rj)
+		for i, x := range publics[j] {
+			t.Mul(rj, x)
+			icScalars[i+1].Add(icScalars[i+1], t)
+		}
+		points = append(points, g1.MulScalar(&bls.PointG1{}, proofs[j].A, rs[j]))
+		twistPoints = append(twistPoints, proofs[j].B)
+	}
+	icBig := make([]*big.Int, len(icScalars))
+	for i := range icScalars {
+		icBig[i] = icScalars[i].ToBig()
+	}
+	l, err := g1.MultiExp(&bls.PointG1{}, vk.IC, icBig)
+	if err != nil {
+		return false, err
+	}
+	cs := make([]*bls.PointG1, k)
+	for j := range proofs {
+		cs[j] = proofs[j].C
+	}
+	c, err := g1.MultiExp(&bls.PointG1{}, cs, rs)
+	if err != nil {
+		return false, err
+	}
+	points = append(points, l, c)
+	twistPoints = append(twistPoints, vk.negGamma, vk.negDelta)
+	f := pair(e, points, twistPoints)
+	expected := e.Fp12.NewElement()
+	e.Fp12.Exp(expected, vk.alphaBeta, sum.ToBig())
+	return e.Fp12.Equal(f, expected), nil
+}
+
+// pair returns product of pairings. Pairs with a point at infinity contribute
+// identity and are skipped.
+func pair(e *bls.BLSPairingEngine, points []*bls.PointG1, twistPoints []*bls.PointG2) *bls.Fe12 {
+	ps := make([]bls.PointG1, 0, len(points))
+	qs := make([]bls.PointG2, 0, len(points))
+	for i := range points {
+		if e.G1.IsZero(points[i]) || e.G2.IsZero(twistPoints[i]) {
+			continue
+		}
+		ps = append(ps, *points[i])
+		qs = append(qs, *twistPoints[i])
+	}
+	f := e.Fp12.Zero()
+	e.Pair(f, ps, qs)
+	return f
+}
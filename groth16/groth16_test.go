@@ -0,0 +1,190 @@
+package groth16
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+type fixture struct {
+	vk      *VerifyingKey
+	proofs  []*Proof
+	publics [][]*bls.Fr
+}
+
+func open(t *testing.T, name string) io.Reader {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(data)
+}
+
+func loadGnark(t *testing.T) *fixture {
+	vk, err := ReadGnarkVerifyingKey(open(t, "gnark/verification_key.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fixture{vk: vk}
+	for i := 0; i < 2; i++ {
+		proof, err := ReadGnarkProof(open(t, fmt.Sprintf("gnark/proof_%d.bin", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		public, err := ReadGnarkPublicWitness(open(t, fmt.Sprintf("gnark/public_%d.bin", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.proofs, f.publics = append(f.proofs, proof), append(f.publics, public)
+	}
+	return f
+}
+
+func loadSnarkJS(t *testing.T) *fixture {
+	vk, err := ReadSnarkJSVerifyingKey(open(t, "snarkjs/verification_key.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fixture{vk: vk}
+	for i := 0; i < 2; i++ {
+		proof, err := ReadSnarkJSProof(open(t, fmt.Sprintf("snarkjs/proof_%d.json", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		public, err := ReadSnarkJSPublicInputs(open(t, fmt.Sprintf("snarkjs/public_%d.json", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.proofs, f.publics = append(f.proofs, proof), append(f.publics, public)
+	}
+	return f
+}
+
+func TestVerify(t *testing.T) {
+	for name, load := range map[string]func(*testing.T) *fixture{"gnark": loadGnark, "snarkjs": loadSnarkJS} {
+		t.Run(name, func(t *testing.T) {
+			f := load(t)
+			if f.vk.NumPublicInputs() != 2 {
+				t.Fatalf("bad number of public inputs")
+			}
+			for i := range f.proofs {
+				ok, err := f.vk.Verify(f.proofs[i], f.publics[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					t.Fatalf("valid proof %d is rejected", i)
+				}
+			}
+			// proof of one statement does not verify another
+			if ok, _ := f.vk.Verify(f.proofs[0], f.publics[1]); ok {
+				t.Fatalf("proof is accepted with wrong public inputs")
+			}
+			swapped := &Proof{A: f.proofs[0].A, B: f.proofs[0].B, C: f.proofs[1].C}
+			if ok, _ := f.vk.Verify(swapped, f.publics[0]); ok {
+				t.Fatalf("bad proof is accepted")
+			}
+			if _, err := f.vk.Verify(f.proofs[0], f.publics[0][1:]); err != ErrPublicInputs {
+				t.Fatalf("wrong number of public inputs is accepted")
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	a, b := loadGnark(t), loadSnarkJS(t)
+	if !g1.Equal(a.vk.Alpha, b.vk.Alpha) || !g2.Equal(a.vk.Beta, b.vk.Beta) ||
+		!g2.Equal(a.vk.Gamma, b.vk.Gamma) || !g2.Equal(a.vk.Delta, b.vk.Delta) || len(a.vk.IC) != len(b.vk.IC) {
+		t.Fatalf("verifying keys differ")
+	}
+	for i := range a.proofs {
+		if !g1.Equal(a.proofs[i].A, b.proofs[i].A) || !g2.Equal(a.proofs[i].B, b.proofs[i].B) ||
+			!g1.Equal(a.proofs[i].C, b.proofs[i].C) {
+			t.Fatalf("proofs differ")
+		}
+		for j := range a.publics[i] {
+			if !a.publics[i][j].Equal(b.publics[i][j]) {
+				t.Fatalf("public inputs differ")
+			}
+		}
+	}
+	t.Run("Invalid", func(t *testing.T) {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "gnark", "proof_0.bin"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// proof without commitment section of older versions
+		if _, err := ReadGnarkProof(bytes.NewReader(data[:192])); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadGnarkProof(bytes.NewReader(data[:191])); err == nil {
+			t.Fatalf("truncated proof is accepted")
+		}
+		bad := append([]byte{}, data...)
+		bad[195] = 1
+		if _, err := ReadGnarkProof(bytes.NewReader(bad)); err == nil {
+			t.Fatalf("proof with commitments is accepted")
+		}
+		data, err = ioutil.ReadFile(filepath.Join("testdata", "snarkjs", "verification_key.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bad = bytes.Replace(data, []byte(`"bls12381"`), []byte(`"bn128"`), 1)
+		if _, err := ReadSnarkJSVerifyingKey(bytes.NewReader(bad)); err == nil {
+			t.Fatalf("key of another curve is accepted")
+		}
+		if _, err := ReadSnarkJSPublicInputs(bytes.NewReader([]byte(`["-1"]`))); err == nil {
+			t.Fatalf("negative public input is accepted")
+		}
+	})
+}
+
+func TestBatchVerify(t *testing.T) {
+	f := loadGnark(t)
+	// batch may contain the same proof more than once
+	proofs := append(f.proofs, f.proofs...)
+	publics := append(f.publics, f.publics...)
+	ok, err := f.vk.BatchVerify(proofs, publics, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("valid proofs are rejected")
+	}
+	if ok, err := f.vk.BatchVerify(nil, nil, nil); err != nil || !ok {
+		t.Fatalf("empty batch is rejected")
+	}
+	publics = [][]*bls.Fr{f.publics[0], f.publics[0]}
+	if ok, _ := f.vk.BatchVerify(f.proofs, publics, nil); ok {
+		t.Fatalf("proof with wrong public inputs is accepted")
+	}
+	if _, err := f.vk.BatchVerify(f.proofs, publics[:1], nil); err == nil {
+		t.Fatalf("inputs of different lengths are accepted")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	vk, _ := ReadGnarkVerifyingKey(mustOpen(b, "gnark/verification_key.bin"))
+	proof, _ := ReadGnarkProof(mustOpen(b, "gnark/proof_0.bin"))
+	public, _ := ReadGnarkPublicWitness(mustOpen(b, "gnark/public_0.bin"))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vk.Verify(proof, public)
+	}
+}
+
+func mustOpen(b *testing.B, name string) io.Reader {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return f
+}
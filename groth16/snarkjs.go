@@ -0,0 +1,191 @@
+package groth16
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// snarkjs encodes points in projective coordinates as decimal strings, G1
+// as [x, y, z] and G2 as [[x0, x1], [y0, y1], [z0, z1]] where x = x0 + x1 * u.
+// Points are expected in affine form with z = 1, or z = 0 for infinity.
+//
+// Unverified: this layout, including the [c0, c1] order of Fp2 elements, is
+// taken from the snarkjs JSON schema and is only tested against fixtures
+// converted from gnark outputs, not against files written by snarkjs.
+
+type snarkjsVerifyingKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Alpha    []string   `json:"vk_alpha_1"`
+	Beta     [][]string `json:"vk_beta_2"`
+	Gamma    [][]string `json:"vk_gamma_2"`
+	Delta    [][]string `json:"vk_delta_2"`
+	IC       [][]string `json:"IC"`
+}
+
+type snarkjsProof struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	A        []string   `json:"pi_a"`
+	B        [][]string `json:"pi_b"`
+	C        []string   `json:"pi_c"`
+}
+
+// ReadSnarkJSVerifyingKey decodes verification_key.json of snarkjs. It is
+// unverified against snarkjs output.
+func ReadSnarkJSVerifyingKey(r io.Reader) (*VerifyingKey, error) {
+	var v snarkjsVerifyingKey
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := checkSnarkJSHeader(v.Protocol, v.Curve); err != nil {
+		return nil, err
+	}
+	if len(v.IC) != v.NPublic+1 {
+		return nil, fmt.Errorf("%w: expected %d IC points, got %d", ErrPublicInputs, v.NPublic+1, len(v.IC))
+	}
+	alpha, err := decodeSnarkJSG1(v.Alpha)
+	if err != nil {
+		return nil, fmt.Errorf("alpha: %w", err)
+	}
+	var g2 [3]*bls.PointG2
+	for i, p := range [][][]string{v.Beta, v.Gamma, v.Delta} {
+		if g2[i], err = decodeSnarkJSG2(p); err != nil {
+			return nil, fmt.Errorf("G2 point %d: %w", i, err)
+		}
+	}
+	ic := make([]*bls.PointG1, len(v.IC))
+	for i := range v.IC {
+		if ic[i], err = decodeSnarkJSG1(v.IC[i]); err != nil {
+			return nil, fmt.Errorf("IC point %d: %w", i, err)
+		}
+	}
+	return NewVerifyingKey(alpha, g2[0], g2[1], g2[2], ic)
+}
+
+// ReadSnarkJSProof decodes proof.json of snarkjs. It is
+// unverified against snarkjs output.
+func ReadSnarkJSProof(r io.Reader) (*Proof, error) {
+	var v snarkjsProof
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := checkSnarkJSHeader(v.Protocol, v.Curve); err != nil {
+		return nil, err
+	}
+	var err error
+	proof := &Proof{}
+	if proof.A, err = decodeSnarkJSG1(v.A); err != nil {
+		return nil, fmt.Errorf("A: %w", err)
+	}
+	if proof.B, err = decodeSnarkJSG2(v.B); err != nil {
+		return nil, fmt.Errorf("B: %w", err)
+	}
+	if proof.C, err = decodeSnarkJSG1(v.C); err != nil {
+		return nil, fmt.Errorf("C: %w", err)
+	}
+	return proof, nil
+}
+
+// ReadSnarkJSPublicInputs decodes public.json of snarkjs, a list of decimal
+// strings. It is unverified against snarkjs output.
+func ReadSnarkJSPublicInputs(r io.Reader) ([]*bls.Fr, error) {
+	var v []string
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, err
+	}
+	out := make([]*bls.Fr, len(v))
+	for i := range v {
+		x, err := decodeDecimal(v[i])
+		if err != nil {
+			return nil, fmt.Errorf("public input %d: %w", i, err)
+		}
+		b := make([]byte, 32)
+		if x.BitLen() > 256 {
+			return nil, fmt.Errorf("public input %d: %w", i, bls.ErrNonCanonical)
+		}
+		xb := x.Bytes()
+		copy(b[32-len(xb):], xb)
+		if out[i], err = new(bls.Fr).FromBytesCanonical(b); err != nil {
+			return nil, fmt.Errorf("public input %d: %w", i, err)
+		}
+	}
+	return out, nil
+}
+
+func checkSnarkJSHeader(protocol, curve string) error {
+	if protocol != "groth16" || curve != "bls12381" {
+		return fmt.Errorf("%w: protocol %q on curve %q", ErrUnsupported, protocol, curve)
+	}
+	return nil
+}
+
+func decodeDecimal(s string) (*big.Int, error) {
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok || x.Sign() < 0 {
+		return nil, fmt.Errorf("invalid decimal %q", s)
+	}
+	return x, nil
+}
+
+// decodeCoordinates decodes decimal field elements into big endian 48 bytes
+// each.
+func decodeCoordinates(in []string) ([]byte, error) {
+	out := make([]byte, 48*len(in))
+	for i := range in {
+		x, err := decodeDecimal(in[i])
+		if err != nil {
+			return nil, err
+		}
+		if x.BitLen() > 384 {
+			return nil, fmt.Errorf("%w: field element is too large", bls.ErrNonCanonical)
+		}
+		b := x.Bytes()
+		copy(out[48*(i+1)-len(b):], b)
+	}
+	return out, nil
+}
+
+func decodeSnarkJSG1(in []string) (*bls.PointG1, error) {
+	if len(in) != 3 {
+		return nil, fmt.Errorf("%w: G1 point should have 3 coordinates", bls.ErrInvalidLength)
+	}
+	g := bls.NewG1(bls.NewFp())
+	switch in[2] {
+	case "0":
+		return g.Zero(), nil
+	case "1":
+	default:
+		return nil, fmt.Errorf("%w: point is not affine", ErrUnsupported)
+	}
+	b, err := decodeCoordinates(in[:2])
+	if err != nil {
+		return nil, err
+	}
+	return g.FromUncompressed(b)
+}
+
+func decodeSnarkJSG2(in [][]string) (*bls.PointG2, error) {
+	if len(in) != 3 || len(in[0]) != 2 || len(in[1]) != 2 || len(in[2]) != 2 {
+		return nil, fmt.Errorf("%w: G2 point should have 3 coordinates of 2 elements", bls.ErrInvalidLength)
+	}
+	g := bls.NewG2(bls.NewFp2(bls.NewFp()))
+	switch {
+	case in[2][0] == "0" && in[2][1] == "0":
+		return g.Zero(), nil
+	case in[2][0] == "1" && in[2][1] == "0":
+	default:
+		return nil, fmt.Errorf("%w: point is not affine", ErrUnsupported)
+	}
+	// serialized Fp2 element has the imaginary part first
+	b, err := decodeCoordinates([]string{in[0][1], in[0][0], in[1][1], in[1][0]})
+	if err != nil {
+		return nil, err
+	}
+	return g.FromUncompressed(b)
+}
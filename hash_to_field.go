@@ -0,0 +1,135 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// domain separation tags longer than maxDSTLength are hashed first
+const maxDSTLength = 255
+
+var oversizeDSTPrefix = []byte("H2C-OVERSIZE-DST-")
+
+// ExpandMsgXMD implements expand_message_xmd with a Merkle-Damgard hash
+// function such as SHA-256. Given hash is reset before it is used.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-5.4.1
+func ExpandMsgXMD(h hash.Hash, msg, dst []byte, outLen int) ([]byte, error) {
+	bLen := h.Size()
+	ell := (outLen + bLen - 1) / bLen
+	if ell > 255 || outLen > 65535 || outLen < 0 {
+		return nil, errors.New("invalid output length for expand_message_xmd")
+	}
+	if len(dst) > maxDSTLength {
+		h.Reset()
+		h.Write(oversizeDSTPrefix)
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	h.Reset()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(msg)
+	h.Write([]byte{byte(outLen >> 8), byte(outLen), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+	out := append(make([]byte, 0, ell*bLen), bi...)
+	for i := 2; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:outLen], nil
+}
+
+// ExpandMsgXOF implements expand_message_xof with an extendable output
+// function, SHAKE-128 or SHAKE-256, where k is the target security level in
+// bits. Given hash is reset before it is used.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-5.4.2
+func ExpandMsgXOF(h sha3.ShakeHash, k int, msg, dst []byte, outLen int) ([]byte, error) {
+	if outLen > 65535 || outLen < 0 {
+		return nil, errors.New("invalid output length for expand_message_xof")
+	}
+	if len(dst) > maxDSTLength {
+		h.Reset()
+		h.Write(oversizeDSTPrefix)
+		h.Write(dst)
+		dst = make([]byte, (2*k+7)/8)
+		h.Read(dst)
+	}
+	h.Reset()
+	h.Write(msg)
+	h.Write([]byte{byte(outLen >> 8), byte(outLen)})
+	h.Write(dst)
+	h.Write([]byte{byte(len(dst))})
+	out := make([]byte, outLen)
+	h.Read(out)
+	return out, nil
+}
+
+// HashToFp hashes a message to count field elements with expand_message_xmd
+// and SHA-256. This is hash_to_field of BLS12381G1_XMD:SHA-256_SSWU suites.
+func HashToFp(msg, dst []byte, count int) ([]*Fe, error) {
+	f := NewFp()
+	// L = ceil((ceil(log2(p)) + k) / 8) where k = 128
+	const L = 64
+	b, err := ExpandMsgXMD(sha256.New(), msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+	p := modulus.Big()
+	out := make([]*Fe, count)
+	for i := 0; i < count; i++ {
+		e := new(big.Int).SetBytes(b[i*L : (i+1)*L])
+		if out[i], err = f.NewElementFromBig(e.Mod(e, p)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// HashToFp2 hashes a message to count quadratic extension field elements
+// with expand_message_xmd and SHA-256. This is hash_to_field of
+// BLS12381G2_XMD:SHA-256_SSWU suites.
+func HashToFp2(msg, dst []byte, count int) ([]*Fe2, error) {
+	u, err := HashToFp(msg, dst, 2*count)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Fe2, count)
+	for i := 0; i < count; i++ {
+		out[i] = &Fe2{*u[2*i], *u[2*i+1]}
+	}
+	return out, nil
+}
+
+// HashToFr hashes a message to count scalars modulo curve order with
+// expand_message_xmd and SHA-256.
+func HashToFr(msg, dst []byte, count int) ([]*big.Int, error) {
+	// L = ceil((ceil(log2(q)) + k) / 8) where k = 128
+	const L = 48
+	b, err := ExpandMsgXMD(sha256.New(), msg, dst, count*L)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*big.Int, count)
+	for i := 0; i < count; i++ {
+		e := new(big.Int).SetBytes(b[i*L : (i+1)*L])
+		out[i] = e.Mod(e, q)
+	}
+	return out, nil
+}
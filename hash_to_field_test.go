@@ -0,0 +1,183 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+func TestExpandMsg(t *testing.T) {
+	files := []string{
+		"expand_message_xmd_SHA256_38.json",
+		"expand_message_xmd_SHA256_256.json",
+		"expand_message_xmd_SHA512_38.json",
+		"expand_message_xof_SHAKE128_36.json",
+		"expand_message_xof_SHAKE128_256.json",
+		"expand_message_xof_SHAKE256_36.json",
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile("tests/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v struct {
+			DST   string
+			Hash  string
+			K     int
+			Tests []struct {
+				LenInBytes   string `json:"len_in_bytes"`
+				Msg          string `json:"msg"`
+				UniformBytes string `json:"uniform_bytes"`
+			}
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		t.Run(file, func(t *testing.T) {
+			for _, c := range v.Tests {
+				outLen, err := strconv.ParseInt(c.LenInBytes, 0, 32)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var out []byte
+				switch v.Hash {
+				case "SHA256":
+					out, err = ExpandMsgXMD(sha256.New(), []byte(c.Msg), []byte(v.DST), int(outLen))
+				case "SHA512":
+					out, err = ExpandMsgXMD(sha512.New(), []byte(c.Msg), []byte(v.DST), int(outLen))
+				case "SHAKE128":
+					out, err = ExpandMsgXOF(sha3.NewShake128(), v.K, []byte(c.Msg), []byte(v.DST), int(outLen))
+				case "SHAKE256":
+					out, err = ExpandMsgXOF(sha3.NewShake256(), v.K, []byte(c.Msg), []byte(v.DST), int(outLen))
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hex.EncodeToString(out) != c.UniformBytes {
+					t.Fatalf("bad expansion for %q, %d bytes", c.Msg, outLen)
+				}
+			}
+		})
+	}
+	if _, err := ExpandMsgXMD(sha256.New(), nil, nil, 255*32+1); err == nil {
+		t.Fatalf("too long output should be rejected")
+	}
+	if _, err := ExpandMsgXOF(sha3.NewShake128(), 128, nil, nil, 1<<16); err == nil {
+		t.Fatalf("too long output should be rejected")
+	}
+}
+
+type hashToCurveVectors struct {
+	DST     string
+	Vectors []struct {
+		Msg string
+		P   struct{ X, Y string }
+		U   []string
+	}
+}
+
+func loadHashToCurveVectors(t *testing.T, file string) hashToCurveVectors {
+	data, err := ioutil.ReadFile("tests/" + file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v hashToCurveVectors
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestHashToFp(t *testing.T) {
+	f := NewFp()
+	g1 := NewG1(f)
+	v := loadHashToCurveVectors(t, "BLS12381G1_XMD-SHA-256_SSWU_RO_.json")
+	for _, c := range v.Vectors {
+		u, err := HashToFp([]byte(c.Msg), []byte(v.DST), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			expected, err := f.NewElementFromString(c.U[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !f.Equal(u[i], expected) {
+				t.Fatalf("bad field element for %q", c.Msg)
+			}
+		}
+		p, err := g1.HashToCurve([]byte(c.Msg), []byte(v.DST))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.ToString(&p[0]) != c.P.X || f.ToString(&p[1]) != c.P.Y {
+			t.Fatalf("bad hash to curve for %q", c.Msg)
+		}
+	}
+}
+
+func TestHashToFp2(t *testing.T) {
+	f := NewFp()
+	fp2 := NewFp2(f)
+	g2 := NewG2(fp2)
+	fe2String := func(a *Fe2) string {
+		return f.ToString(&a[0]) + "," + f.ToString(&a[1])
+	}
+	v := loadHashToCurveVectors(t, "BLS12381G2_XMD-SHA-256_SSWU_RO_.json")
+	for _, c := range v.Vectors {
+		u, err := HashToFp2([]byte(c.Msg), []byte(v.DST), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if fe2String(u[i]) != c.U[i] {
+				t.Fatalf("bad field element for %q", c.Msg)
+			}
+		}
+		p, err := g2.HashToCurve([]byte(c.Msg), []byte(v.DST))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fe2String(&p[0]) != c.P.X || fe2String(&p[1]) != c.P.Y {
+			t.Fatalf("bad hash to curve for %q", c.Msg)
+		}
+	}
+}
+
+func TestHashToFr(t *testing.T) {
+	dst := []byte("BLS12381-TEST-HASH-TO-FR")
+	a, err := HashToFr([]byte("msg"), dst, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := HashToFr([]byte("msg"), dst, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if a[i].Cmp(b[i]) != 0 {
+			t.Fatalf("hashing is not deterministic")
+		}
+		if a[i].Sign() < 0 || a[i].Cmp(q) >= 0 {
+			t.Fatalf("scalar is not reduced")
+		}
+	}
+	if a[0].Cmp(a[1]) == 0 {
+		t.Fatalf("scalars should be distinct")
+	}
+	c, err := HashToFr([]byte("msg"), []byte(strings.Repeat("x", 300)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(c[0].Bytes(), a[0].Bytes()) {
+		t.Fatalf("different tags should give different scalars")
+	}
+}
@@ -0,0 +1,118 @@
+// Package gnark decodes the binary encoding that gnark writes with WriteTo,
+// shared by the Groth16 and PLONK verifiers. Integers are big endian, field
+// elements are 32 bytes big endian, points are compressed in zcash format and
+// slices have a uint32 length prefix.
+package gnark
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Reader reads values of gnark encoding in sequence.
+type Reader struct {
+	r  *bufio.Reader
+	g1 *bls.G1
+	g2 *bls.G2
+}
+
+// NewReader returns a reader of r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{
+		r:  bufio.NewReader(r),
+		g1: bls.NewG1(bls.NewFp()),
+		g2: bls.NewG2(bls.NewFp2(bls.NewFp())),
+	}
+}
+
+// G1Point reads a compressed G1 point, checked to be in the subgroup.
+func (d *Reader) G1Point() (*bls.PointG1, error) {
+	b := make([]byte, 48)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, err
+	}
+	return d.g1.FromCompressedStrict(b)
+}
+
+// G2Point reads a compressed G2 point, checked to be in the subgroup.
+func (d *Reader) G2Point() (*bls.PointG2, error) {
+	b := make([]byte, 96)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, err
+	}
+	return d.g2.FromCompressedStrict(b)
+}
+
+// Scalar reads a canonical scalar.
+func (d *Reader) Scalar() (*bls.Fr, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return nil, err
+	}
+	return new(bls.Fr).FromBytesCanonical(b)
+}
+
+// Uint32 reads a big endian uint32.
+func (d *Reader) Uint32() (uint32, error) {
+	var n uint32
+	err := binary.Read(d.r, binary.BigEndian, &n)
+	return n, err
+}
+
+// Uint64 reads a big endian uint64.
+func (d *Reader) Uint64() (uint64, error) {
+	var n uint64
+	err := binary.Read(d.r, binary.BigEndian, &n)
+	return n, err
+}
+
+// Discard skips n bytes.
+func (d *Reader) Discard(n int) error {
+	_, err := d.r.Discard(n)
+	return err
+}
+
+// End reports whether input is consumed.
+func (d *Reader) End() bool {
+	_, err := d.r.Peek(1)
+	return err == io.EOF
+}
+
+// ReadPublicWitness decodes public witness written by gnark's
+// Witness.WriteTo, uint32(public) uint32(secret) uint32(len) inputs.
+func ReadPublicWitness(r io.Reader) ([]*bls.Fr, error) {
+	d := NewReader(r)
+	public, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	n, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	if secret != 0 || n != public {
+		return nil, errors.New("witness should have public inputs only")
+	}
+	// length is not trusted for allocation
+	var out []*bls.Fr
+	for i := uint32(0); i < n; i++ {
+		x, err := d.Scalar()
+		if err != nil {
+			return nil, fmt.Errorf("public input %d: %w", i, err)
+		}
+		out = append(out, x)
+	}
+	if !d.End() {
+		return nil, errors.New("unexpected trailing data")
+	}
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package gnark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func witness(public, secret, n uint32, inputs ...*bls.Fr) []byte {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint32(b[0:], public)
+	binary.BigEndian.PutUint32(b[4:], secret)
+	binary.BigEndian.PutUint32(b[8:], n)
+	for _, x := range inputs {
+		b = append(b, x.ToBytes()...)
+	}
+	return b
+}
+
+func TestReadPublicWitness(t *testing.T) {
+	x, y := new(bls.Fr).SetUint64(35), new(bls.Fr).SetUint64(21)
+	t.Run("valid", func(t *testing.T) {
+		out, err := ReadPublicWitness(bytes.NewReader(witness(2, 0, 2, x, y)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) != 2 || !out[0].Equal(x) || !out[1].Equal(y) {
+			t.Fatalf("bad public inputs")
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		nonCanonical := witness(1, 0, 1)
+		nonCanonical = append(nonCanonical, bytes.Repeat([]byte{0xff}, 32)...)
+		for name, b := range map[string][]byte{
+			"secret inputs":   witness(2, 1, 3, x, y, x),
+			"truncated":       witness(2, 0, 2, x),
+			"trailing data":   append(witness(1, 0, 1, x), 0),
+			"non canonical":   nonCanonical,
+			"large length":    witness(1<<31, 0, 1<<31),
+			"length mismatch": witness(1, 0, 2, x, y),
+		} {
+			if _, err := ReadPublicWitness(bytes.NewReader(b)); err == nil {
+				t.Fatalf("%s should fail", name)
+			}
+		}
+	})
+}
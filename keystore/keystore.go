@@ -0,0 +1,292 @@
+// Package keystore implements EIP-2335 encrypted keystores for BLS secret
+// keys.
+// https://eips.ethereum.org/EIPS/eip-2335
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	bls "github.com/kilic/bls12-381"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Supported key derivation functions.
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+const version = 4
+
+// Errors returned by Decrypt.
+var (
+	ErrInvalidPassword = errors.New("invalid password")
+	ErrPubkeyMismatch  = errors.New("public key does not match secret key")
+	ErrUnsupported     = errors.New("unsupported keystore")
+)
+
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+type Crypto struct {
+	KDF      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Module is a step of encryption. Params are decoded depending on the
+// function.
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Encrypt encrypts a secret key with a password using given key derivation
+// function with parameters suggested by EIP-2335. Public key of secret key
+// and path that it is derived from are stored in plain.
+func Encrypt(sk *big.Int, password, path, kdf string) (*Keystore, error) {
+	if !validSecretKey(sk) {
+		return nil, errors.New("secret key should be in range of (0, q)")
+	}
+	salt, iv, id := make([]byte, 32), make([]byte, 16), make([]byte, 16)
+	for _, b := range [][]byte{salt, iv, id} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+	var params interface{}
+	switch kdf {
+	case KDFScrypt:
+		params = &scryptParams{DKLen: 32, N: 262144, P: 1, R: 8, Salt: hex.EncodeToString(salt)}
+	case KDFPBKDF2:
+		params = &pbkdf2Params{DKLen: 32, C: 262144, PRF: "hmac-sha256", Salt: hex.EncodeToString(salt)}
+	default:
+		return nil, fmt.Errorf("%w: kdf %q", ErrUnsupported, kdf)
+	}
+	ks := &Keystore{
+		Pubkey:  hex.EncodeToString(publicKey(sk)),
+		Path:    path,
+		UUID:    uuid(id),
+		Version: version,
+	}
+	var err error
+	if ks.Crypto.KDF, err = newModule(kdf, params); err != nil {
+		return nil, err
+	}
+	if ks.Crypto.Cipher, err = newModule("aes-128-ctr", &cipherParams{IV: hex.EncodeToString(iv)}); err != nil {
+		return nil, err
+	}
+	if ks.Crypto.Checksum, err = newModule("sha256", struct{}{}); err != nil {
+		return nil, err
+	}
+	key, err := ks.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	b := sk.Bytes()
+	copy(secret[32-len(b):], b)
+	message, err := aes128CTR(key[:16], iv, secret)
+	if err != nil {
+		return nil, err
+	}
+	ks.Crypto.Cipher.Message = hex.EncodeToString(message)
+	ks.Crypto.Checksum.Message = hex.EncodeToString(checksum(key, message))
+	return ks, nil
+}
+
+// Decrypt decrypts secret key with password. If public key field is not empty
+// it is checked against the public key derived from decrypted secret key.
+func (ks *Keystore) Decrypt(password string) (*big.Int, error) {
+	if ks.Version != version {
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupported, ks.Version)
+	}
+	if ks.Crypto.Checksum.Function != "sha256" {
+		return nil, fmt.Errorf("%w: checksum %q", ErrUnsupported, ks.Crypto.Checksum.Function)
+	}
+	if ks.Crypto.Cipher.Function != "aes-128-ctr" {
+		return nil, fmt.Errorf("%w: cipher %q", ErrUnsupported, ks.Crypto.Cipher.Function)
+	}
+	var params cipherParams
+	if err := json.Unmarshal(ks.Crypto.Cipher.Params, &params); err != nil {
+		return nil, err
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil {
+		return nil, err
+	}
+	message, err := hex.DecodeString(ks.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := hex.DecodeString(ks.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.decryptionKey(password)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(checksum(key, message), expected) {
+		return nil, ErrInvalidPassword
+	}
+	secret, err := aes128CTR(key[:16], iv, message)
+	if err != nil {
+		return nil, err
+	}
+	if len(secret) != 32 {
+		return nil, fmt.Errorf("%w: secret key of %d bytes", bls.ErrInvalidLength, len(secret))
+	}
+	sk := new(big.Int).SetBytes(secret)
+	if !validSecretKey(sk) {
+		return nil, errors.New("secret key should be in range of (0, q)")
+	}
+	if ks.Pubkey != "" {
+		pubkey, err := hex.DecodeString(ks.Pubkey)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pubkey, publicKey(sk)) {
+			return nil, ErrPubkeyMismatch
+		}
+	}
+	return sk, nil
+}
+
+func (ks *Keystore) decryptionKey(password string) ([]byte, error) {
+	kdf := ks.Crypto.KDF
+	pw := normalizePassword(password)
+	switch kdf.Function {
+	case KDFScrypt:
+		var params scryptParams
+		if err := json.Unmarshal(kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.DKLen < 32 {
+			return nil, fmt.Errorf("%w: dklen %d", ErrUnsupported, params.DKLen)
+		}
+		return scrypt.Key(pw, salt, params.N, params.R, params.P, params.DKLen)
+	case KDFPBKDF2:
+		var params pbkdf2Params
+		if err := json.Unmarshal(kdf.Params, &params); err != nil {
+			return nil, err
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if params.PRF != "hmac-sha256" {
+			return nil, fmt.Errorf("%w: prf %q", ErrUnsupported, params.PRF)
+		}
+		if params.DKLen < 32 || params.C <= 0 {
+			return nil, fmt.Errorf("%w: pbkdf2 parameters", ErrUnsupported)
+		}
+		return pbkdf2.Key(pw, salt, params.C, params.DKLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("%w: kdf %q", ErrUnsupported, kdf.Function)
+}
+
+func newModule(function string, params interface{}) (Module, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return Module{}, err
+	}
+	return Module{Function: function, Params: raw}, nil
+}
+
+func checksum(key, message []byte) []byte {
+	h := sha256.New()
+	h.Write(key[16:32])
+	h.Write(message)
+	return h.Sum(nil)
+}
+
+func aes128CTR(key, iv, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, errors.New("invalid iv length")
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// normalizePassword applies NFKD normalization and strips C0, C1 and Delete
+// control codes.
+func normalizePassword(password string) []byte {
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, norm.NFKD.String(password)))
+}
+
+// validSecretKey reports whether sk is a non zero scalar less than curve
+// order.
+func validSecretKey(sk *big.Int) bool {
+	if sk.Sign() <= 0 || sk.BitLen() > 256 {
+		return false
+	}
+	b := make([]byte, 32)
+	skb := sk.Bytes()
+	copy(b[32-len(skb):], skb)
+	_, err := new(bls.Fr).FromBytesCanonical(b)
+	return err == nil
+}
+
+func publicKey(sk *big.Int) []byte {
+	g := bls.NewG1(bls.NewFp())
+	return g.ToCompressed(g.MulScalar(g.Zero(), &bls.G1One, sk))
+}
+
+func uuid(b []byte) string {
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,123 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// password of EIP-2335 test vectors, normalized to "testpassword🔑"
+const testPassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+
+var testSecret, _ = new(big.Int).SetString("000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f", 16)
+
+func loadKeystore(t *testing.T, file string) *Keystore {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := &Keystore{}
+	if err := json.Unmarshal(data, ks); err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+func TestDecryptVectors(t *testing.T) {
+	for _, file := range []string{"scrypt.json", "pbkdf2.json"} {
+		t.Run(file, func(t *testing.T) {
+			ks := loadKeystore(t, file)
+			sk, err := ks.Decrypt(testPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sk.Cmp(testSecret) != 0 {
+				t.Fatalf("bad secret key")
+			}
+		})
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	ks := loadKeystore(t, "pbkdf2.json")
+	if _, err := ks.Decrypt("testpassword"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected %v, got %v", ErrInvalidPassword, err)
+	}
+	ks.Pubkey = "a" + ks.Pubkey[1:]
+	if _, err := ks.Decrypt(testPassword); !errors.Is(err, ErrPubkeyMismatch) {
+		t.Fatalf("expected %v, got %v", ErrPubkeyMismatch, err)
+	}
+	// secret key must be 32 bytes even if a shorter one is in range
+	ks = loadKeystore(t, "pbkdf2.json")
+	key, err := ks.decryptionKey(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := ks.Crypto.Cipher.Message
+	ks.Crypto.Cipher.Message = message[:len(message)-2]
+	short, _ := hex.DecodeString(ks.Crypto.Cipher.Message)
+	ks.Crypto.Checksum.Message = hex.EncodeToString(checksum(key, short))
+	ks.Pubkey = ""
+	if _, err := ks.Decrypt(testPassword); !errors.Is(err, bls.ErrInvalidLength) {
+		t.Fatalf("expected %v, got %v", bls.ErrInvalidLength, err)
+	}
+	ks = loadKeystore(t, "pbkdf2.json")
+	ks.Crypto.Cipher.Function = "aes-256-gcm"
+	if _, err := ks.Decrypt(testPassword); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+	ks = loadKeystore(t, "pbkdf2.json")
+	ks.Version = 3
+	if _, err := ks.Decrypt(testPassword); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, kdf := range []string{KDFScrypt, KDFPBKDF2} {
+		t.Run(kdf, func(t *testing.T) {
+			ks, err := Encrypt(testSecret, testPassword, "m/12381/3600/0/0/0", kdf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(ks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ks2 := &Keystore{}
+			if err := json.Unmarshal(data, ks2); err != nil {
+				t.Fatal(err)
+			}
+			if ks2.Pubkey != loadKeystore(t, "scrypt.json").Pubkey {
+				t.Fatalf("bad public key")
+			}
+			// password is normalized before key derivation
+			sk, err := ks2.Decrypt("testpassword\U0001f511")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sk.Cmp(testSecret) != 0 {
+				t.Fatalf("bad secret key")
+			}
+		})
+	}
+	if _, err := Encrypt(testSecret, testPassword, "", "argon2"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected %v, got %v", ErrUnsupported, err)
+	}
+	if _, err := Encrypt(new(big.Int), testPassword, "", KDFPBKDF2); err == nil {
+		t.Fatalf("zero secret key should be rejected")
+	}
+	order, _ := new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	if _, err := Encrypt(order, testPassword, "", KDFPBKDF2); err == nil {
+		t.Fatalf("secret key equal to curve order should be rejected")
+	}
+	if !validSecretKey(new(big.Int).Sub(order, big.NewInt(1))) || validSecretKey(new(big.Int).Lsh(order, 8)) {
+		t.Fatalf("bad secret key range check")
+	}
+}
@@ -0,0 +1,166 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"runtime"
+	"sync"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// Contribute updates transcript with a fresh secret x read from r, if r is
+// nil crypto/rand is used. Powers are multiplied as tau^i * x^i, and the
+// running product x * p with public key x * G2 is appended to the witness as
+// proof of knowledge of x. The secret and its powers are cleared from
+// memory held by this package before returning, copies made by the runtime
+// or by group arithmetic are not. Transcript is expected to be verified
+// beforehand.
+func (t *Transcript) Contribute(r io.Reader) error {
+	if len(t.RunningProducts) == 0 {
+		return errors.New("transcript has no witness")
+	}
+	x, err := newSecret(r)
+	if err != nil {
+		return err
+	}
+	t.contribute(x, "")
+	x.Zero()
+	return nil
+}
+
+// newSecret reads a non zero secret from r.
+func newSecret(r io.Reader) (*bls.Fr, error) {
+	x, err := new(bls.Fr).Rand(r)
+	if err != nil {
+		return nil, err
+	}
+	if x.IsZero() {
+		return nil, errors.New("zero secret")
+	}
+	return x, nil
+}
+
+// contribute updates transcript with secret x and appends signature to the
+// witness.
+func (t *Transcript) contribute(x *bls.Fr, signature string) {
+	mulPowersG1(t.G1Powers, x)
+	mulPowersG2(t.G2Powers, x)
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	k := x.ToBig()
+	p := g1.MulScalar(&bls.PointG1{}, t.RunningProducts[len(t.RunningProducts)-1], k)
+	pk := g2.MulScalar(&bls.PointG2{}, &bls.G2One, k)
+	clearBig(k)
+	g1.Affine(p)
+	g2.Affine(pk)
+	t.RunningProducts = append(t.RunningProducts, p)
+	t.PotPubkeys = append(t.PotPubkeys, pk)
+	t.BLSSignatures = append(t.BLSSignatures, signature)
+}
+
+// Contribute updates each transcript with its own fresh secret and records
+// participant id. Contributions are not signed, so empty signatures are
+// appended to the witness. All secrets are drawn before any transcript is
+// updated, so the batch is left unchanged on error.
+func (b *BatchTranscript) Contribute(id string, r io.Reader) error {
+	for i, t := range b.Transcripts {
+		if len(t.RunningProducts) == 0 {
+			return fmt.Errorf("transcript %d has no witness", i)
+		}
+	}
+	secrets := make([]*bls.Fr, len(b.Transcripts))
+	defer func() {
+		for _, x := range secrets {
+			if x != nil {
+				x.Zero()
+			}
+		}
+	}()
+	for i := range secrets {
+		x, err := newSecret(r)
+		if err != nil {
+			return err
+		}
+		secrets[i] = x
+	}
+	for i, t := range b.Transcripts {
+		t.contribute(secrets[i], "")
+	}
+	b.ParticipantIDs = append(b.ParticipantIDs, id)
+	b.ParticipantECDSASignatures = append(b.ParticipantECDSASignatures, "")
+	return nil
+}
+
+// mulPowersG1 multiplies points with successive powers of x in place,
+// P_i = x^i * P_i. Powers are split into a chunk per CPU and every worker
+// starts from x^i of its first index.
+func mulPowersG1(points []*bls.PointG1, x *bls.Fr) {
+	chunks(len(points), func(start, end int) {
+		g := bls.NewG1(bls.NewFp())
+		e := new(bls.Fr)
+		e.Exp(x, big.NewInt(int64(start)))
+		for i := start; i < end; i++ {
+			k := e.ToBig()
+			g.MulScalar(points[i], points[i], k)
+			clearBig(k)
+			g.Affine(points[i])
+			e.Mul(e, x)
+		}
+		e.Zero()
+	})
+}
+
+// mulPowersG2 multiplies points with successive powers of x in place,
+// P_i = x^i * P_i.
+func mulPowersG2(points []*bls.PointG2, x *bls.Fr) {
+	chunks(len(points), func(start, end int) {
+		g := bls.NewG2(bls.NewFp2(bls.NewFp()))
+		e := new(bls.Fr)
+		e.Exp(x, big.NewInt(int64(start)))
+		for i := start; i < end; i++ {
+			k := e.ToBig()
+			g.MulScalar(points[i], points[i], k)
+			clearBig(k)
+			g.Affine(points[i])
+			e.Mul(e, x)
+		}
+		e.Zero()
+	})
+}
+
+// clearBig overwrites words of k with zeros.
+func clearBig(k *big.Int) {
+	words := k.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	k.SetUint64(0)
+}
+
+// chunks splits [0, n) into a range per CPU and calls f for each range
+// concurrently.
+func chunks(n int, f func(start, end int)) {
+	workers := runtime.NumCPU()
+	if workers > n {
+		workers = n
+	}
+	if workers == 0 {
+		return
+	}
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
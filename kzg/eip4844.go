@@ -0,0 +1,445 @@
+package kzg
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/fft"
+)
+
+// EIP-4844 parameters.
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+const (
+	BytesPerFieldElement = 32
+	FieldElementsPerBlob = 4096
+	BytesPerBlob         = BytesPerFieldElement * FieldElementsPerBlob
+	BytesPerCommitment   = 48
+	BytesPerProof        = 48
+)
+
+var (
+	fiatShamirDomain  = []byte("FSBLOBVERIFY_V1_")
+	batchChallengeDst = []byte("RCKZGBATCH___V1_")
+)
+
+// Errors returned by EIP-4844 functions.
+var (
+	ErrInvalidScalar = errors.New("invalid scalar")
+	ErrInvalidPoint  = errors.New("invalid point")
+)
+
+// Blob is FieldElementsPerBlob scalars in evaluation form, 32 bytes big
+// endian each.
+type Blob [BytesPerBlob]byte
+
+// Commitment is a compressed G1 point.
+type Commitment [BytesPerCommitment]byte
+
+// Proof is a compressed G1 point.
+type Proof [BytesPerProof]byte
+
+// Scalar is a 32 bytes big endian scalar field element.
+type Scalar [BytesPerFieldElement]byte
+
+// Context holds setup of EIP-4844 in the form used by the specification,
+// Lagrange basis in G1 and roots of unity in bit reversal order, and monomial
+// basis in G2.
+type Context struct {
+	roots      []*bls.Fr
+	g1Lagrange []*bls.PointG1
+	g2         []*bls.PointG2
+	invWidth   *bls.Fr
+}
+
+// NewContext creates context from G1 points in Lagrange basis in natural
+// order and G2 points in monomial basis.
+func NewContext(g1Lagrange []*bls.PointG1, g2Monomial []*bls.PointG2) (*Context, error) {
+	if len(g1Lagrange) != FieldElementsPerBlob {
+		return nil, fmt.Errorf("%w: expected %d G1 points, got %d", ErrSRSTooShort, FieldElementsPerBlob, len(g1Lagrange))
+	}
+	if len(g2Monomial) < 2 {
+		return nil, fmt.Errorf("%w: expected at least 2 G2 points, got %d", ErrSRSTooShort, len(g2Monomial))
+	}
+	d, err := fft.NewDomain(FieldElementsPerBlob)
+	if err != nil {
+		return nil, err
+	}
+	c := &Context{
+		roots:      d.Roots,
+		g1Lagrange: make([]*bls.PointG1, FieldElementsPerBlob),
+		g2:         g2Monomial,
+		invWidth:   d.SizeInv,
+	}
+	copy(c.g1Lagrange, g1Lagrange)
+	if err := fft.BitReverseFr(c.roots); err != nil {
+		return nil, err
+	}
+	if err := fft.BitReverseG1(c.g1Lagrange); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadTrustedSetup reads setup in the text format distributed with the
+// reference implementation: number of G1 and G2 points in first two lines
+// followed by G1 points in Lagrange basis and G2 points in monomial basis,
+// each a hex encoded compressed point in a line.
+func LoadTrustedSetup(r io.Reader) (*Context, error) {
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	s := bufio.NewScanner(r)
+	next := func() ([]byte, error) {
+		if !s.Scan() {
+			if err := s.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.ErrUnexpectedEOF
+		}
+		return []byte(strings.TrimSpace(s.Text())), nil
+	}
+	counts := [2]int{}
+	for i := range counts {
+		line, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if counts[i], err = strconv.Atoi(string(line)); err != nil {
+			return nil, fmt.Errorf("bad point count: %v", err)
+		}
+	}
+	if counts[0] != FieldElementsPerBlob || counts[1] < 2 {
+		return nil, fmt.Errorf("%w: %d G1 and %d G2 points", ErrSRSTooShort, counts[0], counts[1])
+	}
+	g1Points := make([]*bls.PointG1, counts[0])
+	for i := range g1Points {
+		line, err := next()
+		if err != nil {
+			return nil, err
+		}
+		in, err := hex.DecodeString(string(line))
+		if err != nil {
+			return nil, err
+		}
+		if g1Points[i], err = g1.FromCompressedStrict(in); err != nil {
+			return nil, fmt.Errorf("G1 point %d: %w", i, err)
+		}
+	}
+	g2Points := make([]*bls.PointG2, counts[1])
+	for i := range g2Points {
+		line, err := next()
+		if err != nil {
+			return nil, err
+		}
+		in, err := hex.DecodeString(string(line))
+		if err != nil {
+			return nil, err
+		}
+		if g2Points[i], err = g2.FromCompressedStrict(in); err != nil {
+			return nil, fmt.Errorf("G2 point %d: %w", i, err)
+		}
+	}
+	return NewContext(g1Points, g2Points)
+}
+
+// LoadTrustedSetupFile reads setup from a file in format of LoadTrustedSetup.
+func LoadTrustedSetupFile(path string) (*Context, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadTrustedSetup(f)
+}
+
+// BlobToKZGCommitment implements blob_to_kzg_commitment.
+func (c *Context) BlobToKZGCommitment(blob *Blob) (Commitment, error) {
+	poly, err := blobToPolynomial(blob)
+	if err != nil {
+		return Commitment{}, err
+	}
+	p, err := c.commit(poly)
+	if err != nil {
+		return Commitment{}, err
+	}
+	var out Commitment
+	copy(out[:], bls.NewG1(bls.NewFp()).ToCompressed(p))
+	return out, nil
+}
+
+// ComputeKZGProof implements compute_kzg_proof. It returns proof of
+// evaluation of blob polynomial at z and the evaluation.
+func (c *Context) ComputeKZGProof(blob *Blob, z Scalar) (Proof, Scalar, error) {
+	poly, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	zz, err := bytesToField(z[:])
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	proof, y, err := c.computeProof(poly, zz)
+	if err != nil {
+		return Proof{}, Scalar{}, err
+	}
+	var out Scalar
+	copy(out[:], y.ToBytes())
+	return proof, out, nil
+}
+
+// ComputeBlobKZGProof implements compute_blob_kzg_proof. It returns proof of
+// evaluation of blob polynomial at Fiat-Shamir challenge of blob and
+// commitment.
+func (c *Context) ComputeBlobKZGProof(blob *Blob, commitment Commitment) (Proof, error) {
+	if _, err := decodeG1(commitment[:]); err != nil {
+		return Proof{}, err
+	}
+	poly, err := blobToPolynomial(blob)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof, _, err := c.computeProof(poly, computeChallenge(blob, commitment))
+	return proof, err
+}
+
+// VerifyKZGProof implements verify_kzg_proof. Error is returned for
+// malformed input and false for a proof that does not verify.
+func (c *Context) VerifyKZGProof(commitment Commitment, z, y Scalar, proof Proof) (bool, error) {
+	cc, err := decodeG1(commitment[:])
+	if err != nil {
+		return false, err
+	}
+	zz, err := bytesToField(z[:])
+	if err != nil {
+		return false, err
+	}
+	yy, err := bytesToField(y[:])
+	if err != nil {
+		return false, err
+	}
+	p, err := decodeG1(proof[:])
+	if err != nil {
+		return false, err
+	}
+	s := &SRS{G2: c.g2}
+	return s.Verify(cc, zz, yy, p), nil
+}
+
+// VerifyBlobKZGProof implements verify_blob_kzg_proof.
+func (c *Context) VerifyBlobKZGProof(blob *Blob, commitment Commitment, proof Proof) (bool, error) {
+	return c.VerifyBlobKZGProofBatch([]*Blob{blob}, []Commitment{commitment}, []Proof{proof})
+}
+
+// VerifyBlobKZGProofBatch implements verify_blob_kzg_proof_batch. Proofs are
+// verified with a random linear combination whose randomness is derived from
+// all inputs.
+func (c *Context) VerifyBlobKZGProofBatch(blobs []*Blob, commitments []Commitment, proofs []Proof) (bool, error) {
+	k := len(blobs)
+	if len(commitments) != k || len(proofs) != k {
+		return false, errors.New("blobs, commitments and proofs should be in same length")
+	}
+	cs, ps := make([]*bls.PointG1, k), make([]*bls.PointG1, k)
+	zs, ys := make([]*bls.Fr, k), make([]*bls.Fr, k)
+	for i := 0; i < k; i++ {
+		var err error
+		if cs[i], err = decodeG1(commitments[i][:]); err != nil {
+			return false, err
+		}
+		if ps[i], err = decodeG1(proofs[i][:]); err != nil {
+			return false, err
+		}
+		poly, err := blobToPolynomial(blobs[i])
+		if err != nil {
+			return false, err
+		}
+		zs[i] = computeChallenge(blobs[i], commitments[i])
+		ys[i] = c.evaluate(poly, zs[i])
+	}
+	if k == 0 {
+		return true, nil
+	}
+	if k == 1 {
+		s := &SRS{G2: c.g2}
+		return s.Verify(cs[0], zs[0], ys[0], ps[0]), nil
+	}
+	// r = hash(domain, width, k, (commitment, z, y, proof)...)
+	h := sha256.New()
+	h.Write(batchChallengeDst)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], FieldElementsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(k))
+	h.Write(buf[:])
+	for i := 0; i < k; i++ {
+		h.Write(commitments[i][:])
+		h.Write(zs[i].ToBytes())
+		h.Write(ys[i].ToBytes())
+		h.Write(proofs[i][:])
+	}
+	r := new(bls.Fr).FromBytes(h.Sum(nil))
+	rs := make([]*bls.Fr, k)
+	rs[0] = new(bls.Fr).One()
+	for i := 1; i < k; i++ {
+		rs[i] = new(bls.Fr)
+		rs[i].Mul(rs[i-1], r)
+	}
+	s := &SRS{G2: c.g2}
+	return s.batchVerify(cs, zs, ys, ps, rs)
+}
+
+// commit returns linear combination of Lagrange basis with evaluations.
+func (c *Context) commit(poly []*bls.Fr) (*bls.PointG1, error) {
+	s := &SRS{G1: c.g1Lagrange}
+	return s.Commit(poly)
+}
+
+// computeProof implements compute_kzg_proof_impl. Quotient
+// (p(X) - y) / (X - z) is computed in evaluation form.
+func (c *Context) computeProof(poly []*bls.Fr, z *bls.Fr) (Proof, *bls.Fr, error) {
+	y := c.evaluate(poly, z)
+	quotient := make([]*bls.Fr, len(poly))
+	denominators := make([]*bls.Fr, len(poly))
+	index := -1
+	for i := range poly {
+		quotient[i] = new(bls.Fr)
+		quotient[i].Sub(poly[i], y)
+		denominators[i] = new(bls.Fr)
+		denominators[i].Sub(c.roots[i], z)
+		if denominators[i].IsZero() {
+			index = i
+		}
+	}
+	batchInverse(denominators)
+	for i := range poly {
+		quotient[i].Mul(quotient[i], denominators[i])
+	}
+	if index >= 0 {
+		// q(z) = sum_{w_i != z} (p_i - y) * w_i / (z * (z - w_i))
+		q := quotient[index]
+		q.Zero()
+		t := new(bls.Fr)
+		for i := range poly {
+			if i == index {
+				continue
+			}
+			denominators[i].Sub(z, c.roots[i])
+			denominators[i].Mul(denominators[i], z)
+		}
+		denominators[index].One()
+		batchInverse(denominators)
+		for i := range poly {
+			if i == index {
+				continue
+			}
+			t.Sub(poly[i], y)
+			t.Mul(t, c.roots[i])
+			t.Mul(t, denominators[i])
+			q.Add(q, t)
+		}
+	}
+	p, err := c.commit(quotient)
+	if err != nil {
+		return Proof{}, nil, err
+	}
+	var out Proof
+	copy(out[:], bls.NewG1(bls.NewFp()).ToCompressed(p))
+	return out, y, nil
+}
+
+// evaluate implements evaluate_polynomial_in_evaluation_form with barycentric
+// formula, p(z) = (z^n - 1) / n * sum p_i * w_i / (z - w_i).
+func (c *Context) evaluate(poly []*bls.Fr, z *bls.Fr) *bls.Fr {
+	denominators := make([]*bls.Fr, len(poly))
+	for i := range poly {
+		if c.roots[i].Equal(z) {
+			return new(bls.Fr).Set(poly[i])
+		}
+		denominators[i] = new(bls.Fr)
+		denominators[i].Sub(z, c.roots[i])
+	}
+	batchInverse(denominators)
+	y, t := new(bls.Fr), new(bls.Fr)
+	for i := range poly {
+		t.Mul(poly[i], c.roots[i])
+		t.Mul(t, denominators[i])
+		y.Add(y, t)
+	}
+	t.Exp(z, big.NewInt(int64(len(poly))))
+	t.Sub(t, new(bls.Fr).One())
+	y.Mul(y, t)
+	y.Mul(y, c.invWidth)
+	return y
+}
+
+// computeChallenge implements compute_challenge.
+func computeChallenge(blob *Blob, commitment Commitment) *bls.Fr {
+	h := sha256.New()
+	h.Write(fiatShamirDomain)
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], FieldElementsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+	return new(bls.Fr).FromBytes(h.Sum(nil))
+}
+
+func blobToPolynomial(blob *Blob) ([]*bls.Fr, error) {
+	poly := make([]*bls.Fr, FieldElementsPerBlob)
+	for i := range poly {
+		e, err := bytesToField(blob[i*BytesPerFieldElement : (i+1)*BytesPerFieldElement])
+		if err != nil {
+			return nil, fmt.Errorf("field element %d: %w", i, err)
+		}
+		poly[i] = e
+	}
+	return poly, nil
+}
+
+func bytesToField(in []byte) (*bls.Fr, error) {
+	e, err := new(bls.Fr).FromBytesCanonical(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScalar, err)
+	}
+	return e, nil
+}
+
+// decodeG1 decodes a commitment or a proof which must be a valid compressed
+// point in G1 or the point at infinity.
+func decodeG1(in []byte) (*bls.PointG1, error) {
+	p, err := bls.NewG1(bls.NewFp()).FromCompressedStrict(in)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPoint, err)
+	}
+	return p, nil
+}
+
+// batchInverse inverts non zero elements in place with Montgomery's trick.
+// Zero elements are left as is.
+func batchInverse(in []*bls.Fr) {
+	acc := new(bls.Fr).One()
+	prefix := make([]*bls.Fr, len(in))
+	for i, e := range in {
+		prefix[i] = new(bls.Fr).Set(acc)
+		if !e.IsZero() {
+			acc.Mul(acc, e)
+		}
+	}
+	acc.Inverse(acc)
+	t := new(bls.Fr)
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i].IsZero() {
+			continue
+		}
+		t.Mul(acc, prefix[i])
+		acc.Mul(acc, in[i])
+		in[i].Set(t)
+	}
+}
@@ -0,0 +1,301 @@
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+var (
+	testContext     *Context
+	testContextErr  error
+	testContextOnce sync.Once
+)
+
+func loadTestContext(t testing.TB) *Context {
+	testContextOnce.Do(func() {
+		testContext, testContextErr = LoadTrustedSetupFile(filepath.Join("testdata", "trusted_setup.txt"))
+	})
+	if testContextErr != nil {
+		t.Fatal(testContextErr)
+	}
+	return testContext
+}
+
+func TestLoadTrustedSetup(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "trusted_setup.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	// points with trailing bytes are rejected in both groups
+	for _, i := range []int{2, 2 + FieldElementsPerBlob} {
+		bad := append([]string{}, lines...)
+		bad[i] += "00"
+		if _, err := LoadTrustedSetup(strings.NewReader(strings.Join(bad, "\n"))); !errors.Is(err, bls.ErrInvalidLength) {
+			t.Fatalf("line %d: expected ErrInvalidLength, got %v", i, err)
+		}
+	}
+}
+
+type eip4844Vector struct {
+	Name  string `json:"name"`
+	Input struct {
+		Blob        string   `json:"blob"`
+		Blobs       []string `json:"blobs"`
+		Commitment  string   `json:"commitment"`
+		Commitments []string `json:"commitments"`
+		Proof       string   `json:"proof"`
+		Proofs      []string `json:"proofs"`
+		Z           string   `json:"z"`
+		Y           string   `json:"y"`
+	} `json:"input"`
+	Output json.RawMessage `json:"output"`
+}
+
+// loadEIP4844Vectors loads consensus spec vectors of a function. Blobs are
+// kept in blobs.json and referred by name in vectors.
+func loadEIP4844Vectors(t *testing.T, function string) ([]eip4844Vector, map[string]string) {
+	var vectors []eip4844Vector
+	var blobs map[string]string
+	for file, v := range map[string]interface{}{function + ".json": &vectors, "blobs.json": &blobs} {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "eip4844", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return vectors, blobs
+}
+
+// decodeFixed decodes hex input of exactly size bytes. Inputs of other sizes
+// are rejected by type system of the API so the vector is expected to fail.
+func decodeFixed(in string, out []byte) bool {
+	b, err := hex.DecodeString(strings.TrimPrefix(in, "0x"))
+	if err != nil || len(b) != len(out) {
+		return false
+	}
+	copy(out, b)
+	return true
+}
+
+func hexOf(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, blobs := loadEIP4844Vectors(t, "blob_to_kzg_commitment")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected *string
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			blob := new(Blob)
+			ok := decodeFixed(blobs[v.Input.Blob], blob[:])
+			var commitment Commitment
+			var err error
+			if ok {
+				commitment, err = c.BlobToKZGCommitment(blob)
+			}
+			if expected == nil {
+				if ok && err == nil {
+					t.Fatalf("expected failure")
+				}
+				return
+			}
+			if !ok || err != nil {
+				t.Fatalf("unexpected failure: %v", err)
+			}
+			if hexOf(commitment[:]) != *expected {
+				t.Fatalf("bad commitment\nhave %x\nwant %s", commitment, *expected)
+			}
+		})
+	}
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, blobs := loadEIP4844Vectors(t, "compute_kzg_proof")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected []string
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			blob := new(Blob)
+			var z Scalar
+			ok := decodeFixed(blobs[v.Input.Blob], blob[:]) && decodeFixed(v.Input.Z, z[:])
+			var proof Proof
+			var y Scalar
+			var err error
+			if ok {
+				proof, y, err = c.ComputeKZGProof(blob, z)
+			}
+			if expected == nil {
+				if ok && err == nil {
+					t.Fatalf("expected failure")
+				}
+				return
+			}
+			if !ok || err != nil {
+				t.Fatalf("unexpected failure: %v", err)
+			}
+			if hexOf(proof[:]) != expected[0] || hexOf(y[:]) != expected[1] {
+				t.Fatalf("bad proof or evaluation")
+			}
+		})
+	}
+}
+
+func TestComputeBlobKZGProof(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, blobs := loadEIP4844Vectors(t, "compute_blob_kzg_proof")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected *string
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			blob := new(Blob)
+			var commitment Commitment
+			ok := decodeFixed(blobs[v.Input.Blob], blob[:]) && decodeFixed(v.Input.Commitment, commitment[:])
+			var proof Proof
+			var err error
+			if ok {
+				proof, err = c.ComputeBlobKZGProof(blob, commitment)
+			}
+			if expected == nil {
+				if ok && err == nil {
+					t.Fatalf("expected failure")
+				}
+				return
+			}
+			if !ok || err != nil {
+				t.Fatalf("unexpected failure: %v", err)
+			}
+			if hexOf(proof[:]) != *expected {
+				t.Fatalf("bad proof")
+			}
+		})
+	}
+}
+
+func TestVerifyKZGProof(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, _ := loadEIP4844Vectors(t, "verify_kzg_proof")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected *bool
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			var commitment Commitment
+			var z, y Scalar
+			var proof Proof
+			ok := decodeFixed(v.Input.Commitment, commitment[:]) && decodeFixed(v.Input.Z, z[:]) &&
+				decodeFixed(v.Input.Y, y[:]) && decodeFixed(v.Input.Proof, proof[:])
+			var valid bool
+			var err error
+			if ok {
+				valid, err = c.VerifyKZGProof(commitment, z, y, proof)
+			}
+			checkVerification(t, expected, ok, valid, err)
+		})
+	}
+}
+
+func TestVerifyBlobKZGProof(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, blobs := loadEIP4844Vectors(t, "verify_blob_kzg_proof")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected *bool
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			blob := new(Blob)
+			var commitment Commitment
+			var proof Proof
+			ok := decodeFixed(blobs[v.Input.Blob], blob[:]) && decodeFixed(v.Input.Commitment, commitment[:]) &&
+				decodeFixed(v.Input.Proof, proof[:])
+			var valid bool
+			var err error
+			if ok {
+				valid, err = c.VerifyBlobKZGProof(blob, commitment, proof)
+			}
+			checkVerification(t, expected, ok, valid, err)
+		})
+	}
+}
+
+func TestVerifyBlobKZGProofBatch(t *testing.T) {
+	c := loadTestContext(t)
+	vectors, blobs := loadEIP4844Vectors(t, "verify_blob_kzg_proof_batch")
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			var expected *bool
+			if err := json.Unmarshal(v.Output, &expected); err != nil {
+				t.Fatal(err)
+			}
+			ok := true
+			bs := make([]*Blob, len(v.Input.Blobs))
+			for i := range bs {
+				bs[i] = new(Blob)
+				ok = ok && decodeFixed(blobs[v.Input.Blobs[i]], bs[i][:])
+			}
+			commitments := make([]Commitment, len(v.Input.Commitments))
+			for i := range commitments {
+				ok = ok && decodeFixed(v.Input.Commitments[i], commitments[i][:])
+			}
+			proofs := make([]Proof, len(v.Input.Proofs))
+			for i := range proofs {
+				ok = ok && decodeFixed(v.Input.Proofs[i], proofs[i][:])
+			}
+			var valid bool
+			var err error
+			if ok {
+				valid, err = c.VerifyBlobKZGProofBatch(bs, commitments, proofs)
+			}
+			checkVerification(t, expected, ok, valid, err)
+		})
+	}
+}
+
+func checkVerification(t *testing.T, expected *bool, ok, valid bool, err error) {
+	t.Helper()
+	if expected == nil {
+		if ok && err == nil {
+			t.Fatalf("expected failure")
+		}
+		return
+	}
+	if !ok || err != nil {
+		t.Fatalf("unexpected failure: %v", err)
+	}
+	if valid != *expected {
+		t.Fatalf("expected %v, got %v", *expected, valid)
+	}
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	c := loadTestContext(b)
+	blob := new(Blob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		blob[i*BytesPerFieldElement+31] = byte(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.BlobToKZGCommitment(blob)
+	}
+}
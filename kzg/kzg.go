@@ -0,0 +1,223 @@
+// Package kzg implements Kate-Zaverucha-Goldberg polynomial commitments over
+// BLS12-381. Polynomials are given with coefficients in increasing degree
+// order.
+// https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/poly"
+)
+
+// ErrSRSTooShort is returned when a polynomial or an opening set does not fit
+// into the reference string.
+var ErrSRSTooShort = errors.New("reference string is too short")
+
+// SRS is structured reference string, powers of a secret tau in G1 and G2.
+type SRS struct {
+	// G1 is tau^i * G1One for i in [0, len(G1))
+	G1 []*bls.PointG1
+	// G2 is tau^i * G2One for i in [0, len(G2))
+	G2 []*bls.PointG2
+}
+
+// NewSRS generates reference string with given number of powers from a known
+// secret. Anyone knowing tau can forge openings, so it must only be used for
+// testing.
+func NewSRS(tau *bls.Fr, g1Size, g2Size int) *SRS {
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	s := &SRS{
+		G1: make([]*bls.PointG1, g1Size),
+		G2: make([]*bls.PointG2, g2Size),
+	}
+	t := new(bls.Fr).One()
+	for i := 0; i < g1Size || i < g2Size; i++ {
+		if i < g1Size {
+			s.G1[i] = g1.MulScalar(&bls.PointG1{}, &bls.G1One, t.ToBig())
+			g1.Affine(s.G1[i])
+		}
+		if i < g2Size {
+			s.G2[i] = g2.MulScalar(&bls.PointG2{}, &bls.G2One, t.ToBig())
+			g2.Affine(s.G2[i])
+		}
+		t.Mul(t, tau)
+	}
+	return s
+}
+
+// Commit returns commitment to polynomial, p(tau) * G1One.
+func (s *SRS) Commit(p []*bls.Fr) (*bls.PointG1, error) {
+	if len(p) > len(s.G1) {
+		return nil, fmt.Errorf("%w: polynomial of %d coefficients", ErrSRSTooShort, len(p))
+	}
+	g := bls.NewG1(bls.NewFp())
+	if len(p) == 0 {
+		return g.Zero(), nil
+	}
+	c, err := g.MultiExp(&bls.PointG1{}, s.G1[:len(p)], frsToBig(p))
+	if err != nil {
+		return nil, err
+	}
+	g.Affine(c)
+	return c, nil
+}
+
+// Open evaluates polynomial at z and returns the evaluation with a proof,
+// commitment to quotient (p(X) - p(z)) / (X - z).
+func (s *SRS) Open(p []*bls.Fr, z *bls.Fr) (*bls.PointG1, *bls.Fr, error) {
+	q, y := poly.DivideByLinear(p, z)
+	proof, err := s.Commit(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, y, nil
+}
+
+// Verify checks that committed polynomial evaluates to y at z, that is,
+// e(C - y * G1, G2) == e(proof, tau * G2 - z * G2).
+func (s *SRS) Verify(commitment *bls.PointG1, z, y *bls.Fr, proof *bls.PointG1) bool {
+	if len(s.G2) < 2 {
+		return false
+	}
+	e := bls.NewBLSPairingEngine()
+	g1, g2 := e.G1, e.G2
+	left := g1.MulScalar(&bls.PointG1{}, &bls.G1One, y.ToBig())
+	g1.Sub(left, commitment, left)
+	right := g2.MulScalar(&bls.PointG2{}, &bls.G2One, z.ToBig())
+	g2.Sub(right, s.G2[1], right)
+	return pairingCheck(e, []*bls.PointG1{left, proof}, []*bls.PointG2{g2.Neg(&bls.PointG2{}, &bls.G2One), right})
+}
+
+// OpenMulti evaluates polynomial at distinct points and returns evaluations
+// with a single proof, commitment to quotient (p(X) - I(X)) / Z(X) where I
+// interpolates evaluations and Z vanishes on the points.
+func (s *SRS) OpenMulti(p []*bls.Fr, zs []*bls.Fr) (*bls.PointG1, []*bls.Fr, error) {
+	ys := make([]*bls.Fr, len(zs))
+	for i, z := range zs {
+		ys[i] = poly.Polynomial(p).Eval(z)
+	}
+	// p(X) - I(X) is divisible by Z(X) so the remainder is discarded
+	i, err := poly.Interpolate(zs, ys)
+	if err != nil {
+		return nil, nil, err
+	}
+	q, _, err := poly.Divide(poly.Sub(p, i), poly.Vanishing(zs))
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := s.Commit(q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, ys, nil
+}
+
+// VerifyMulti checks that committed polynomial evaluates to ys at zs, that
+// is, e(C - I(tau) * G1, G2) == e(proof, Z(tau) * G2).
+func (s *SRS) VerifyMulti(commitment *bls.PointG1, zs, ys []*bls.Fr, proof *bls.PointG1) bool {
+	if len(zs) != len(ys) || len(zs) == 0 || len(zs) >= len(s.G2) || len(zs) > len(s.G1) {
+		return false
+	}
+	i, err := poly.Interpolate(zs, ys)
+	if err != nil {
+		return false
+	}
+	e := bls.NewBLSPairingEngine()
+	g1, g2 := e.G1, e.G2
+	left, err := s.Commit(i)
+	if err != nil {
+		return false
+	}
+	g1.Sub(left, commitment, left)
+	vanishing := poly.Vanishing(zs)
+	right, err := g2.MultiExp(&bls.PointG2{}, s.G2[:len(vanishing)], frsToBig(vanishing))
+	if err != nil {
+		return false
+	}
+	return pairingCheck(e, []*bls.PointG1{left, proof}, []*bls.PointG2{g2.Neg(&bls.PointG2{}, &bls.G2One), right})
+}
+
+// BatchVerify checks many single point openings at once with a random linear
+// combination of verification equations,
+// e(sum r_i * (C_i - y_i * G1 + z_i * proof_i), G2) == e(sum r_i * proof_i, tau * G2).
+// Randomness is read from r, if r is nil crypto/rand is used.
+func (s *SRS) BatchVerify(commitments []*bls.PointG1, zs, ys []*bls.Fr, proofs []*bls.PointG1, r io.Reader) (bool, error) {
+	k := len(commitments)
+	if len(zs) != k || len(ys) != k || len(proofs) != k {
+		return false, errors.New("commitments, points, evaluations and proofs should be in same length")
+	}
+	if len(s.G2) < 2 {
+		return false, ErrSRSTooShort
+	}
+	rs := make([]*bls.Fr, k)
+	for i := range rs {
+		ri, err := new(bls.Fr).Rand(r)
+		if err != nil {
+			return false, err
+		}
+		rs[i] = ri
+	}
+	return s.batchVerify(commitments, zs, ys, proofs, rs)
+}
+
+// batchVerify checks single point openings combined with given scalars.
+func (s *SRS) batchVerify(commitments []*bls.PointG1, zs, ys []*bls.Fr, proofs []*bls.PointG1, rs []*bls.Fr) (bool, error) {
+	k := len(commitments)
+	e := bls.NewBLSPairingEngine()
+	g1 := e.G1
+	// sum r_i * C_i + sum r_i z_i * proof_i - (sum r_i y_i) * G1
+	points := make([]*bls.PointG1, 0, 2*k+1)
+	powers := make([]*big.Int, 0, 2*k+1)
+	rProofs := make([]*big.Int, k)
+	ry, t := new(bls.Fr), new(bls.Fr)
+	for i := 0; i < k; i++ {
+		rProofs[i] = rs[i].ToBig()
+		t.Mul(rs[i], zs[i])
+		points = append(points, commitments[i], proofs[i])
+		powers = append(powers, rProofs[i], t.ToBig())
+		t.Mul(rs[i], ys[i])
+		ry.Add(ry, t)
+	}
+	ry.Neg(ry)
+	points = append(points, &bls.G1One)
+	powers = append(powers, ry.ToBig())
+	left, err := g1.MultiExp(&bls.PointG1{}, points, powers)
+	if err != nil {
+		return false, err
+	}
+	right, err := g1.MultiExp(&bls.PointG1{}, proofs, rProofs)
+	if err != nil {
+		return false, err
+	}
+	return pairingCheck(e, []*bls.PointG1{left, g1.Neg(right, right)}, []*bls.PointG2{&bls.G2One, s.G2[1]}), nil
+}
+
+// pairingCheck checks that product of pairings is identity. Pairs with a point
+// at infinity contribute identity and are skipped.
+func pairingCheck(e *bls.BLSPairingEngine, points []*bls.PointG1, twistPoints []*bls.PointG2) bool {
+	ps := make([]bls.PointG1, 0, len(points))
+	qs := make([]bls.PointG2, 0, len(points))
+	for i := range points {
+		if e.G1.IsZero(points[i]) || e.G2.IsZero(twistPoints[i]) {
+			continue
+		}
+		ps = append(ps, *points[i])
+		qs = append(qs, *twistPoints[i])
+	}
+	f := e.Fp12.Zero()
+	e.Pair(f, ps, qs)
+	return e.Fp12.Equal(f, e.Fp12.One())
+}
+
+func frsToBig(in []*bls.Fr) []*big.Int {
+	out := make([]*big.Int, len(in))
+	for i := range in {
+		out[i] = in[i].ToBig()
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package kzg
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/poly"
+)
+
+func randFr(t testing.TB) *bls.Fr {
+	e, err := new(bls.Fr).Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func randPolynomial(t testing.TB, n int) poly.Polynomial {
+	p := make(poly.Polynomial, n)
+	for i := range p {
+		p[i] = randFr(t)
+	}
+	return p
+}
+
+func TestCommit(t *testing.T) {
+	g := bls.NewG1(bls.NewFp())
+	tau := randFr(t)
+	s := NewSRS(tau, 16, 2)
+	p := randPolynomial(t, 16)
+	c, err := s.Commit(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := g.MulScalar(&bls.PointG1{}, &bls.G1One, p.Eval(tau).ToBig())
+	if !g.Equal(c, expected) {
+		t.Fatalf("commitment is not evaluation at tau")
+	}
+	if _, err := s.Commit(randPolynomial(t, 17)); err == nil {
+		t.Fatalf("polynomial larger than reference string is accepted")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	s := NewSRS(randFr(t), 16, 2)
+	p := randPolynomial(t, 16)
+	c, err := s.Commit(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := randFr(t)
+	proof, y, err := s.Open(p, z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !y.Equal(p.Eval(z)) {
+		t.Fatalf("bad evaluation")
+	}
+	if !s.Verify(c, z, y, proof) {
+		t.Fatalf("valid opening is rejected")
+	}
+	bad := new(bls.Fr)
+	bad.Add(y, new(bls.Fr).One())
+	if s.Verify(c, z, bad, proof) {
+		t.Fatalf("bad evaluation is accepted")
+	}
+	if s.Verify(c, bad, y, proof) {
+		t.Fatalf("bad point is accepted")
+	}
+	// opening at a root gives zero evaluation
+	root := randFr(t)
+	q := []*bls.Fr{new(bls.Fr), new(bls.Fr).One()}
+	q[0].Neg(root)
+	c, _ = s.Commit(q)
+	proof, y, err = s.Open(q, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !y.IsZero() || !s.Verify(c, root, y, proof) {
+		t.Fatalf("opening at root is rejected")
+	}
+}
+
+func TestOpenMulti(t *testing.T) {
+	s := NewSRS(randFr(t), 16, 5)
+	p := randPolynomial(t, 16)
+	c, err := s.Commit(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []int{1, 2, 4} {
+		zs := randPolynomial(t, k)
+		proof, ys, err := s.OpenMulti(p, zs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.VerifyMulti(c, zs, ys, proof) {
+			t.Fatalf("valid opening at %d points is rejected", k)
+		}
+		ys[k-1].Add(ys[k-1], new(bls.Fr).One())
+		if s.VerifyMulti(c, zs, ys, proof) {
+			t.Fatalf("bad evaluation at %d points is accepted", k)
+		}
+	}
+	zs := randPolynomial(t, 5)
+	if _, ys, err := s.OpenMulti(p, zs); err != nil || s.VerifyMulti(c, zs, ys, s.G1[0]) {
+		t.Fatalf("opening at more points than reference string is accepted")
+	}
+	zs = []*bls.Fr{zs[0], zs[0]}
+	if _, _, err := s.OpenMulti(p, zs); err == nil {
+		t.Fatalf("opening at duplicate points is accepted")
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	s := NewSRS(randFr(t), 8, 2)
+	k := 5
+	commitments := make([]*bls.PointG1, k)
+	proofs := make([]*bls.PointG1, k)
+	zs := make([]*bls.Fr, k)
+	ys := make([]*bls.Fr, k)
+	for i := 0; i < k; i++ {
+		p := randPolynomial(t, 8)
+		var err error
+		if commitments[i], err = s.Commit(p); err != nil {
+			t.Fatal(err)
+		}
+		zs[i] = randFr(t)
+		if proofs[i], ys[i], err = s.Open(p, zs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ok, err := s.BatchVerify(commitments, zs, ys, proofs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("valid openings are rejected")
+	}
+	ys[2].Add(ys[2], new(bls.Fr).One())
+	if ok, _ := s.BatchVerify(commitments, zs, ys, proofs, nil); ok {
+		t.Fatalf("bad evaluation is accepted")
+	}
+	if _, err := s.BatchVerify(commitments, zs[1:], ys, proofs, nil); err == nil {
+		t.Fatalf("inputs of different lengths are accepted")
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	s := NewSRS(randFr(b), 4096, 2)
+	p := randPolynomial(b, 4096)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Commit(p)
+	}
+}
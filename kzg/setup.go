@@ -0,0 +1,336 @@
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+// ErrInvalidTranscript is returned when a powers of tau transcript does not
+// verify.
+var ErrInvalidTranscript = errors.New("invalid transcript")
+
+// trustedSetupJSON is the trusted setup format of consensus specs.
+type trustedSetupJSON struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// LoadTrustedSetupJSON reads EIP-4844 setup in JSON format of consensus specs
+// with hex encoded compressed points in g1_lagrange and g2_monomial fields.
+func LoadTrustedSetupJSON(r io.Reader) (*Context, error) {
+	var setup trustedSetupJSON
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	g1, err := decodeG1Points(setup.G1Lagrange)
+	if err != nil {
+		return nil, err
+	}
+	g2, err := decodeG2Points(setup.G2Monomial)
+	if err != nil {
+		return nil, err
+	}
+	return NewContext(g1, g2)
+}
+
+// LoadSRS reads reference string in JSON format of consensus specs with hex
+// encoded compressed points in g1_monomial and g2_monomial fields. Powers are
+// not checked, see SRS.CheckPowers.
+func LoadSRS(r io.Reader) (*SRS, error) {
+	var setup trustedSetupJSON
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	g1, err := decodeG1Points(setup.G1Monomial)
+	if err != nil {
+		return nil, err
+	}
+	g2, err := decodeG2Points(setup.G2Monomial)
+	if err != nil {
+		return nil, err
+	}
+	return &SRS{G1: g1, G2: g2}, nil
+}
+
+// CheckPowers checks that reference string starts with generators and
+// consists of successive powers of the same secret in both groups. Successive
+// pairs are combined with random scalars read from r so that the check costs
+// two pairings per group. If r is nil crypto/rand is used.
+func (s *SRS) CheckPowers(r io.Reader) error {
+	if len(s.G1) < 2 || len(s.G2) < 2 {
+		return ErrSRSTooShort
+	}
+	e := bls.NewBLSPairingEngine()
+	g1, g2 := e.G1, e.G2
+	if !g1.Equal(s.G1[0], &bls.G1One) || !g2.Equal(s.G2[0], &bls.G2One) {
+		return fmt.Errorf("%w: first powers are not generators", ErrInvalidTranscript)
+	}
+	if g1.IsZero(s.G1[1]) || g2.IsZero(s.G2[1]) {
+		return fmt.Errorf("%w: secret is zero", ErrInvalidTranscript)
+	}
+	// e(sum r_i * G1_i, tau * G2) == e(sum r_i * G1_{i+1}, G2)
+	rs, err := randomScalars(len(s.G1)-1, r)
+	if err != nil {
+		return err
+	}
+	left, err := g1.MultiExp(&bls.PointG1{}, s.G1[:len(s.G1)-1], rs)
+	if err != nil {
+		return err
+	}
+	right, err := g1.MultiExp(&bls.PointG1{}, s.G1[1:], rs)
+	if err != nil {
+		return err
+	}
+	if !pairingCheck(e, []*bls.PointG1{left, g1.Neg(right, right)}, []*bls.PointG2{s.G2[1], &bls.G2One}) {
+		return fmt.Errorf("%w: G1 powers are not consistent", ErrInvalidTranscript)
+	}
+	// e(tau * G1, sum r_i * G2_i) == e(G1, sum r_i * G2_{i+1})
+	if rs, err = randomScalars(len(s.G2)-1, r); err != nil {
+		return err
+	}
+	left2, err := g2.MultiExp(&bls.PointG2{}, s.G2[:len(s.G2)-1], rs)
+	if err != nil {
+		return err
+	}
+	right2, err := g2.MultiExp(&bls.PointG2{}, s.G2[1:], rs)
+	if err != nil {
+		return err
+	}
+	if !pairingCheck(e, []*bls.PointG1{s.G1[1], g1.Neg(&bls.PointG1{}, &bls.G1One)}, []*bls.PointG2{left2, right2}) {
+		return fmt.Errorf("%w: G2 powers are not consistent", ErrInvalidTranscript)
+	}
+	return nil
+}
+
+// Transcript is a powers of tau transcript with the witness of contributions
+// in JSON format of Ethereum KZG ceremony.
+// https://github.com/ethereum/kzg-ceremony-specs
+type Transcript struct {
+	// Powers of tau where tau is product of secrets of all contributions
+	G1Powers []*bls.PointG1
+	G2Powers []*bls.PointG2
+	// RunningProducts are tau_i * G1 after each contribution i, starting
+	// with G1 generator.
+	RunningProducts []*bls.PointG1
+	// PotPubkeys are x_i * G2 where x_i is secret of contribution i,
+	// starting with G2 generator.
+	PotPubkeys []*bls.PointG2
+	// BLSSignatures are signatures of contributors on their identities. They
+	// are kept as is and not verified.
+	BLSSignatures []string
+}
+
+type transcriptJSON struct {
+	NumG1Powers int `json:"numG1Powers"`
+	NumG2Powers int `json:"numG2Powers"`
+	PowersOfTau struct {
+		G1Powers []string `json:"G1Powers"`
+		G2Powers []string `json:"G2Powers"`
+	} `json:"powersOfTau"`
+	Witness struct {
+		RunningProducts []string `json:"runningProducts"`
+		PotPubkeys      []string `json:"potPubkeys"`
+		BLSSignatures   []string `json:"blsSignatures"`
+	} `json:"witness"`
+}
+
+// BatchTranscript is a set of transcripts of different sizes contributed at
+// once as in output of Ethereum KZG ceremony.
+type BatchTranscript struct {
+	Transcripts                []*Transcript `json:"transcripts"`
+	ParticipantIDs             []string      `json:"participantIds"`
+	ParticipantECDSASignatures []string      `json:"participantEcdsaSignatures"`
+}
+
+// NewTranscript creates transcript of given number of powers with no
+// contributions, that is, tau is one.
+func NewTranscript(numG1Powers, numG2Powers int) *Transcript {
+	t := &Transcript{
+		G1Powers:        make([]*bls.PointG1, numG1Powers),
+		G2Powers:        make([]*bls.PointG2, numG2Powers),
+		RunningProducts: []*bls.PointG1{new(bls.PointG1).Set(&bls.G1One)},
+		PotPubkeys:      []*bls.PointG2{new(bls.PointG2).Set(&bls.G2One)},
+		BLSSignatures:   []string{""},
+	}
+	for i := range t.G1Powers {
+		t.G1Powers[i] = new(bls.PointG1).Set(&bls.G1One)
+	}
+	for i := range t.G2Powers {
+		t.G2Powers[i] = new(bls.PointG2).Set(&bls.G2One)
+	}
+	return t
+}
+
+// LoadBatchTranscript reads and decodes a batch transcript. Contributions are
+// not checked, see BatchTranscript.Verify.
+func LoadBatchTranscript(r io.Reader) (*BatchTranscript, error) {
+	b := &BatchTranscript{}
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Verify verifies all transcripts and checks that they have the same number
+// of contributions. Contributions to different transcripts use independent
+// secrets, so they are not linked to participants beyond their count.
+func (b *BatchTranscript) Verify(r io.Reader) error {
+	if len(b.Transcripts) == 0 {
+		return fmt.Errorf("%w: no transcripts", ErrInvalidTranscript)
+	}
+	for i, t := range b.Transcripts {
+		if err := t.Verify(r); err != nil {
+			return fmt.Errorf("transcript %d: %w", i, err)
+		}
+		if len(t.PotPubkeys) != len(b.Transcripts[0].PotPubkeys) {
+			return fmt.Errorf("%w: transcripts have different number of contributions", ErrInvalidTranscript)
+		}
+	}
+	if len(b.ParticipantIDs) != 0 && len(b.ParticipantIDs) != len(b.Transcripts[0].PotPubkeys) {
+		return fmt.Errorf("%w: number of participants does not match contributions", ErrInvalidTranscript)
+	}
+	return nil
+}
+
+// Verify checks that powers are consistent and that tau is product of
+// secrets of contributions, that is, each running product is the previous
+// one multiplied by secret of a public key of the contribution and the last
+// running product is tau * G1.
+func (t *Transcript) Verify(r io.Reader) error {
+	if len(t.RunningProducts) == 0 || len(t.RunningProducts) != len(t.PotPubkeys) {
+		return fmt.Errorf("%w: running products and public keys should be in same length", ErrInvalidTranscript)
+	}
+	if err := t.SRS().CheckPowers(r); err != nil {
+		return err
+	}
+	e := bls.NewBLSPairingEngine()
+	g1, g2 := e.G1, e.G2
+	if !g1.Equal(t.RunningProducts[0], &bls.G1One) || !g2.Equal(t.PotPubkeys[0], &bls.G2One) {
+		return fmt.Errorf("%w: witness should start with generators", ErrInvalidTranscript)
+	}
+	negG2 := g2.Neg(&bls.PointG2{}, &bls.G2One)
+	for i := 1; i < len(t.RunningProducts); i++ {
+		if g2.IsZero(t.PotPubkeys[i]) {
+			return fmt.Errorf("%w: public key of contribution %d is zero", ErrInvalidTranscript, i)
+		}
+		// e(p_i, G2) == e(p_{i-1}, x_i * G2)
+		if !pairingCheck(e, []*bls.PointG1{t.RunningProducts[i], t.RunningProducts[i-1]}, []*bls.PointG2{negG2, t.PotPubkeys[i]}) {
+			return fmt.Errorf("%w: running product of contribution %d", ErrInvalidTranscript, i)
+		}
+	}
+	if !g1.Equal(t.RunningProducts[len(t.RunningProducts)-1], t.G1Powers[1]) {
+		return fmt.Errorf("%w: last running product is not tau", ErrInvalidTranscript)
+	}
+	return nil
+}
+
+// SRS returns powers of tau as reference string.
+func (t *Transcript) SRS() *SRS {
+	return &SRS{G1: t.G1Powers, G2: t.G2Powers}
+}
+
+func (t *Transcript) MarshalJSON() ([]byte, error) {
+	var v transcriptJSON
+	v.NumG1Powers, v.NumG2Powers = len(t.G1Powers), len(t.G2Powers)
+	v.PowersOfTau.G1Powers = encodeG1Points(t.G1Powers)
+	v.PowersOfTau.G2Powers = encodeG2Points(t.G2Powers)
+	v.Witness.RunningProducts = encodeG1Points(t.RunningProducts)
+	v.Witness.PotPubkeys = encodeG2Points(t.PotPubkeys)
+	v.Witness.BLSSignatures = t.BLSSignatures
+	return json.Marshal(&v)
+}
+
+func (t *Transcript) UnmarshalJSON(data []byte) error {
+	var v transcriptJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.PowersOfTau.G1Powers) != v.NumG1Powers || len(v.PowersOfTau.G2Powers) != v.NumG2Powers {
+		return fmt.Errorf("%w: number of powers does not match", ErrInvalidTranscript)
+	}
+	var err error
+	if t.G1Powers, err = decodeG1Points(v.PowersOfTau.G1Powers); err != nil {
+		return err
+	}
+	if t.G2Powers, err = decodeG2Points(v.PowersOfTau.G2Powers); err != nil {
+		return err
+	}
+	if t.RunningProducts, err = decodeG1Points(v.Witness.RunningProducts); err != nil {
+		return err
+	}
+	if t.PotPubkeys, err = decodeG2Points(v.Witness.PotPubkeys); err != nil {
+		return err
+	}
+	t.BLSSignatures = v.Witness.BLSSignatures
+	return nil
+}
+
+func decodeG1Points(in []string) ([]*bls.PointG1, error) {
+	raw := make([][]byte, len(in))
+	for i := range in {
+		b, err := hex.DecodeString(strings.TrimPrefix(in[i], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("G1 point %d: %w", i, err)
+		}
+		raw[i] = b
+	}
+	out, err := bls.NewG1(bls.NewFp()).BatchFromCompressed(raw)
+	if err != nil {
+		return nil, fmt.Errorf("G1 points: %w", err)
+	}
+	return out, nil
+}
+
+func decodeG2Points(in []string) ([]*bls.PointG2, error) {
+	raw := make([][]byte, len(in))
+	for i := range in {
+		b, err := hex.DecodeString(strings.TrimPrefix(in[i], "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("G2 point %d: %w", i, err)
+		}
+		raw[i] = b
+	}
+	out, err := bls.NewG2(bls.NewFp2(bls.NewFp())).BatchFromCompressed(raw)
+	if err != nil {
+		return nil, fmt.Errorf("G2 points: %w", err)
+	}
+	return out, nil
+}
+
+func encodeG1Points(in []*bls.PointG1) []string {
+	g := bls.NewG1(bls.NewFp())
+	out := make([]string, len(in))
+	for i := range in {
+		out[i] = "0x" + hex.EncodeToString(g.ToCompressed(in[i]))
+	}
+	return out
+}
+
+func encodeG2Points(in []*bls.PointG2) []string {
+	g := bls.NewG2(bls.NewFp2(bls.NewFp()))
+	out := make([]string, len(in))
+	for i := range in {
+		out[i] = "0x" + hex.EncodeToString(g.ToCompressed(in[i]))
+	}
+	return out
+}
+
+func randomScalars(n int, r io.Reader) ([]*big.Int, error) {
+	out := make([]*big.Int, n)
+	for i := range out {
+		e, err := new(bls.Fr).Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e.ToBig()
+	}
+	return out, nil
+}
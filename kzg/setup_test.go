@@ -0,0 +1,237 @@
+package kzg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func loadTestSetupJSON(t *testing.T) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "trusted_setup_4096.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// testTranscript builds a transcript with contributions of given secrets.
+func testTranscript(numG1Powers, numG2Powers int, secrets ...*bls.Fr) *Transcript {
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	tau := new(bls.Fr).One()
+	tr := NewTranscript(numG1Powers, numG2Powers)
+	for _, x := range secrets {
+		tau.Mul(tau, x)
+		p := g1.MulScalar(&bls.PointG1{}, &bls.G1One, tau.ToBig())
+		pk := g2.MulScalar(&bls.PointG2{}, &bls.G2One, x.ToBig())
+		g1.Affine(p)
+		g2.Affine(pk)
+		tr.RunningProducts = append(tr.RunningProducts, p)
+		tr.PotPubkeys = append(tr.PotPubkeys, pk)
+		tr.BLSSignatures = append(tr.BLSSignatures, "")
+	}
+	s := NewSRS(tau, numG1Powers, numG2Powers)
+	tr.G1Powers, tr.G2Powers = s.G1, s.G2
+	return tr
+}
+
+func TestLoadTrustedSetupJSON(t *testing.T) {
+	data := loadTestSetupJSON(t)
+	c, err := LoadTrustedSetupJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := loadTestContext(t)
+	g1 := bls.NewG1(bls.NewFp())
+	for i := range c.g1Lagrange {
+		if !g1.Equal(c.g1Lagrange[i], expected.g1Lagrange[i]) {
+			t.Fatalf("bad lagrange point at %d", i)
+		}
+	}
+	s, err := LoadSRS(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.G1) != FieldElementsPerBlob || len(s.G2) != 65 {
+		t.Fatalf("bad number of powers")
+	}
+	if err := s.CheckPowers(nil); err != nil {
+		t.Fatal(err)
+	}
+	// monomial and lagrange forms commit to the same polynomial
+	blob := new(Blob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		blob[i*BytesPerFieldElement+31] = byte(i)
+	}
+	commitment, err := c.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := new(bls.Fr).SetUint64(5)
+	var zBytes Scalar
+	copy(zBytes[:], z.ToBytes())
+	proof, y, err := c.ComputeKZGProof(blob, zBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yFr, err := new(bls.Fr).FromBytesCanonical(y[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	C, _ := g1.FromCompressed(commitment[:])
+	P, _ := g1.FromCompressed(proof[:])
+	if !s.Verify(C, z, yFr, P) {
+		t.Fatalf("opening is rejected with monomial reference string")
+	}
+}
+
+func TestCheckPowers(t *testing.T) {
+	s := NewSRS(randFr(t), 8, 4)
+	if err := s.CheckPowers(nil); err != nil {
+		t.Fatal(err)
+	}
+	g1, g2 := bls.NewG1(bls.NewFp()), bls.NewG2(bls.NewFp2(bls.NewFp()))
+	t.Run("G1", func(t *testing.T) {
+		bad := &SRS{G1: append([]*bls.PointG1{}, s.G1...), G2: s.G2}
+		bad.G1[5] = g1.Add(&bls.PointG1{}, s.G1[5], &bls.G1One)
+		if err := bad.CheckPowers(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("inconsistent G1 powers are accepted")
+		}
+	})
+	t.Run("G2", func(t *testing.T) {
+		bad := &SRS{G1: s.G1, G2: append([]*bls.PointG2{}, s.G2...)}
+		bad.G2[3] = g2.Add(&bls.PointG2{}, s.G2[3], &bls.G2One)
+		if err := bad.CheckPowers(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("inconsistent G2 powers are accepted")
+		}
+	})
+	t.Run("Generator", func(t *testing.T) {
+		other := NewSRS(randFr(t), 8, 4)
+		bad := &SRS{G1: other.G1[1:], G2: other.G2[1:]}
+		if err := bad.CheckPowers(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("powers not starting with generator are accepted")
+		}
+	})
+}
+
+func TestTranscript(t *testing.T) {
+	tr := testTranscript(8, 3, randFr(t), randFr(t))
+	if err := tr.Verify(nil); err != nil {
+		t.Fatal(err)
+	}
+	// initial transcript of a ceremony
+	if err := NewTranscript(8, 3).Verify(nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("JSON", func(t *testing.T) {
+		b := &BatchTranscript{
+			Transcripts:    []*Transcript{tr, testTranscript(4, 2, randFr(t), randFr(t))},
+			ParticipantIDs: []string{"", "a", "b"},
+		}
+		data, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := LoadBatchTranscript(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := decoded.Verify(nil); err != nil {
+			t.Fatal(err)
+		}
+		again, _ := json.Marshal(decoded)
+		if !bytes.Equal(data, again) {
+			t.Fatalf("encoding does not round trip")
+		}
+	})
+	t.Run("RunningProduct", func(t *testing.T) {
+		bad := testTranscript(8, 3, randFr(t), randFr(t))
+		bad.PotPubkeys[1] = tr.PotPubkeys[1]
+		if err := bad.Verify(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("bad running product is accepted")
+		}
+	})
+	t.Run("Tau", func(t *testing.T) {
+		bad := testTranscript(8, 3, randFr(t), randFr(t))
+		bad.G1Powers, bad.G2Powers = tr.G1Powers, tr.G2Powers
+		if err := bad.Verify(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("powers not matching running product are accepted")
+		}
+	})
+	t.Run("Length", func(t *testing.T) {
+		bad := testTranscript(8, 3, randFr(t))
+		bad.PotPubkeys = bad.PotPubkeys[:1]
+		if err := bad.Verify(nil); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("witness of different lengths is accepted")
+		}
+	})
+	t.Run("Encoding", func(t *testing.T) {
+		data, _ := json.Marshal(tr)
+		bad := bytes.Replace(data, []byte(`"numG1Powers":8`), []byte(`"numG1Powers":9`), 1)
+		if err := json.Unmarshal(bad, new(Transcript)); !errors.Is(err, ErrInvalidTranscript) {
+			t.Fatalf("bad number of powers is accepted")
+		}
+		bad = bytes.Replace(data, []byte(`"0x97f1`), []byte(`"0x17f1`), 1)
+		if err := json.Unmarshal(bad, new(Transcript)); err == nil {
+			t.Fatalf("uncompressed flag is accepted")
+		}
+	})
+}
+
+func TestContribute(t *testing.T) {
+	b := &BatchTranscript{
+		Transcripts:                []*Transcript{NewTranscript(9, 3), NewTranscript(4, 2)},
+		ParticipantIDs:             []string{""},
+		ParticipantECDSASignatures: []string{""},
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := b.Contribute(id, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Verify(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(b.ParticipantIDs) != 3 || len(b.Transcripts[1].PotPubkeys) != 3 {
+		t.Fatalf("contributions are not recorded")
+	}
+	// batch is left unchanged if a secret can not be drawn for every
+	// transcript, reader below gives a single secret
+	g1 := bls.NewG1(bls.NewFp())
+	before := new(bls.PointG1).Set(b.Transcripts[0].G1Powers[1])
+	if err := b.Contribute("c", bytes.NewReader(bytes.Repeat([]byte{1}, 32))); err == nil {
+		t.Fatalf("contribution with short randomness is accepted")
+	}
+	if len(b.ParticipantIDs) != 3 || len(b.Transcripts[0].PotPubkeys) != 3 ||
+		!g1.Equal(b.Transcripts[0].G1Powers[1], before) {
+		t.Fatalf("failed contribution updates batch")
+	}
+	// contributions to different transcripts use different secrets
+	g2 := bls.NewG2(bls.NewFp2(bls.NewFp()))
+	if g2.Equal(b.Transcripts[0].PotPubkeys[1], b.Transcripts[1].PotPubkeys[1]) {
+		t.Fatalf("secret is reused")
+	}
+	// known secrets give known powers
+	x1, x2 := randFr(t), randFr(t)
+	tr := NewTranscript(9, 3)
+	tr.contribute(x1, "")
+	tr.contribute(x2, "")
+	expected := testTranscript(9, 3, x1, x2)
+	for i := range tr.G1Powers {
+		if !g1.Equal(tr.G1Powers[i], expected.G1Powers[i]) {
+			t.Fatalf("bad G1 power at %d", i)
+		}
+	}
+	for i := range tr.G2Powers {
+		if !g2.Equal(tr.G2Powers[i], expected.G2Powers[i]) {
+			t.Fatalf("bad G2 power at %d", i)
+		}
+	}
+	if !g1.Equal(tr.RunningProducts[2], expected.RunningProducts[2]) {
+		t.Fatalf("bad running product")
+	}
+}
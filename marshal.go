@@ -0,0 +1,219 @@
+package bls
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// G1Point, G2Point, FpElement, Fp2Element and Fp12Element wrap group and field
+// elements to implement encoding.BinaryMarshaler, encoding.TextMarshaler and
+// json.Marshaler. Points are encoded in compressed form and decoded with
+// FromCompressedStrict. Field elements are encoded in normal form, big endian
+// and decoding rejects values that are not reduced. Text and JSON encodings
+// are 0x prefixed hex strings of binary encodings.
+type (
+	G1Point     PointG1
+	G2Point     PointG2
+	FpElement   Fe
+	Fp2Element  Fe2
+	Fp12Element Fe12
+)
+
+func (p G1Point) MarshalBinary() ([]byte, error) {
+	return NewG1(NewFp()).ToCompressed((*PointG1)(&p)), nil
+}
+
+func (p *G1Point) UnmarshalBinary(in []byte) error {
+	r, err := NewG1(NewFp()).FromCompressedStrict(in)
+	if err != nil {
+		return err
+	}
+	*p = G1Point(*r)
+	return nil
+}
+
+func (p G1Point) MarshalText() ([]byte, error) {
+	return marshalText(p)
+}
+
+func (p *G1Point) UnmarshalText(in []byte) error {
+	return unmarshalText(p, in)
+}
+
+func (p G1Point) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+func (p *G1Point) UnmarshalJSON(in []byte) error {
+	return unmarshalJSON(p, in)
+}
+
+func (p G2Point) MarshalBinary() ([]byte, error) {
+	return NewG2(NewFp2(NewFp())).ToCompressed((*PointG2)(&p)), nil
+}
+
+func (p *G2Point) UnmarshalBinary(in []byte) error {
+	r, err := NewG2(NewFp2(NewFp())).FromCompressedStrict(in)
+	if err != nil {
+		return err
+	}
+	*p = G2Point(*r)
+	return nil
+}
+
+func (p G2Point) MarshalText() ([]byte, error) {
+	return marshalText(p)
+}
+
+func (p *G2Point) UnmarshalText(in []byte) error {
+	return unmarshalText(p, in)
+}
+
+func (p G2Point) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p)
+}
+
+func (p *G2Point) UnmarshalJSON(in []byte) error {
+	return unmarshalJSON(p, in)
+}
+
+func (fe FpElement) MarshalBinary() ([]byte, error) {
+	return NewFp().ToBytes((*Fe)(&fe)), nil
+}
+
+func (fe *FpElement) UnmarshalBinary(in []byte) error {
+	if len(in) != 48 {
+		return ErrInvalidLength
+	}
+	r := &Fe{}
+	if err := NewFp().NewElementFromBytes(r, in); err != nil {
+		return err
+	}
+	*fe = FpElement(*r)
+	return nil
+}
+
+func (fe FpElement) MarshalText() ([]byte, error) {
+	return marshalText(fe)
+}
+
+func (fe *FpElement) UnmarshalText(in []byte) error {
+	return unmarshalText(fe, in)
+}
+
+func (fe FpElement) MarshalJSON() ([]byte, error) {
+	return marshalJSON(fe)
+}
+
+func (fe *FpElement) UnmarshalJSON(in []byte) error {
+	return unmarshalJSON(fe, in)
+}
+
+func (fe Fp2Element) MarshalBinary() ([]byte, error) {
+	return NewFp2(NewFp()).ToBytes((*Fe2)(&fe)), nil
+}
+
+func (fe *Fp2Element) UnmarshalBinary(in []byte) error {
+	if len(in) != 96 {
+		return ErrInvalidLength
+	}
+	r := &Fe2{}
+	if err := NewFp2(NewFp()).NewElementFromBytes(r, in); err != nil {
+		return err
+	}
+	*fe = Fp2Element(*r)
+	return nil
+}
+
+func (fe Fp2Element) MarshalText() ([]byte, error) {
+	return marshalText(fe)
+}
+
+func (fe *Fp2Element) UnmarshalText(in []byte) error {
+	return unmarshalText(fe, in)
+}
+
+func (fe Fp2Element) MarshalJSON() ([]byte, error) {
+	return marshalJSON(fe)
+}
+
+func (fe *Fp2Element) UnmarshalJSON(in []byte) error {
+	return unmarshalJSON(fe, in)
+}
+
+func (fe Fp12Element) MarshalBinary() ([]byte, error) {
+	return NewFp12(NewFp6(NewFp2(NewFp()))).ToBytes((*Fe12)(&fe)), nil
+}
+
+func (fe *Fp12Element) UnmarshalBinary(in []byte) error {
+	if len(in) != 576 {
+		return ErrInvalidLength
+	}
+	r := &Fe12{}
+	if err := NewFp12(NewFp6(NewFp2(NewFp()))).NewElementFromBytes(r, in); err != nil {
+		return err
+	}
+	*fe = Fp12Element(*r)
+	return nil
+}
+
+func (fe Fp12Element) MarshalText() ([]byte, error) {
+	return marshalText(fe)
+}
+
+func (fe *Fp12Element) UnmarshalText(in []byte) error {
+	return unmarshalText(fe, in)
+}
+
+func (fe Fp12Element) MarshalJSON() ([]byte, error) {
+	return marshalJSON(fe)
+}
+
+func (fe *Fp12Element) UnmarshalJSON(in []byte) error {
+	return unmarshalJSON(fe, in)
+}
+
+type binaryMarshaler interface {
+	MarshalBinary() ([]byte, error)
+}
+
+type binaryUnmarshaler interface {
+	UnmarshalBinary([]byte) error
+}
+
+func marshalText(m binaryMarshaler) ([]byte, error) {
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 2+hex.EncodedLen(len(b)))
+	copy(out, "0x")
+	hex.Encode(out[2:], b)
+	return out, nil
+}
+
+func unmarshalText(u binaryUnmarshaler, in []byte) error {
+	s := strings.TrimPrefix(string(in), "0x")
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalBinary(b)
+}
+
+func marshalJSON(m binaryMarshaler) ([]byte, error) {
+	text, err := marshalText(m)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func unmarshalJSON(u binaryUnmarshaler, in []byte) error {
+	var s string
+	if err := json.Unmarshal(in, &s); err != nil {
+		return err
+	}
+	return unmarshalText(u, []byte(s))
+}
@@ -0,0 +1,116 @@
+package bls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	g1, g2 := NewG1(NewFp()), NewG2(NewFp2(NewFp()))
+	fp12 := NewFp12(nil)
+	type container struct {
+		P1  G1Point
+		P2  G2Point
+		E1  FpElement
+		E2  Fp2Element
+		E12 Fp12Element
+		Z1  G1Point
+	}
+	random := func() *container {
+		c := &container{}
+		c.P1 = G1Point(*g1.MulScalar(&PointG1{}, &G1One, randScalar(q)))
+		c.P2 = G2Point(*g2.MulScalar(&PointG2{}, &G2One, randScalar(q)))
+		c.Z1 = G1Point(*g1.Zero())
+		NewFp().RandElement((*Fe)(&c.E1), rand.Reader)
+		NewFp2(nil).RandElement((*Fe2)(&c.E2), rand.Reader)
+		fp12.RandElement((*Fe12)(&c.E12), rand.Reader)
+		return c
+	}
+	equal := func(a, b *container) bool {
+		return g1.Equal((*PointG1)(&a.P1), (*PointG1)(&b.P1)) &&
+			g2.Equal((*PointG2)(&a.P2), (*PointG2)(&b.P2)) &&
+			g1.IsZero((*PointG1)(&b.Z1)) &&
+			Fe(a.E1) == Fe(b.E1) && Fe2(a.E2) == Fe2(b.E2) &&
+			fp12.Equal((*Fe12)(&a.E12), (*Fe12)(&b.E12))
+	}
+	t.Run("JSON", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b := random(), &container{}
+			data, err := json.Marshal(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(data, b); err != nil {
+				t.Fatal(err)
+			}
+			if !equal(a, b) {
+				t.Fatalf("bad json encoding")
+			}
+		}
+	})
+	t.Run("Gob", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			a, b := random(), &container{}
+			buf := new(bytes.Buffer)
+			if err := gob.NewEncoder(buf).Encode(a); err != nil {
+				t.Fatal(err)
+			}
+			if err := gob.NewDecoder(buf).Decode(b); err != nil {
+				t.Fatal(err)
+			}
+			if !equal(a, b) {
+				t.Fatalf("bad gob encoding")
+			}
+		}
+	})
+	t.Run("Text", func(t *testing.T) {
+		p := G1Point(G1One)
+		text, err := p.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "0x97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"
+		if string(text) != expected {
+			t.Fatalf("bad text encoding of generator\n%s", text)
+		}
+		r := &G1Point{}
+		if err := r.UnmarshalText(text[2:]); err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal((*PointG1)(r), &G1One) {
+			t.Fatalf("bad text decoding")
+		}
+		e := FpElement(*NewFp().One())
+		if text, _ := e.MarshalText(); string(text) != "0x"+padHex("01") {
+			t.Fatalf("bad text encoding of one\n%s", text)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		valid, _ := G1Point(G1One).MarshalBinary()
+		p := G1Point(G1One)
+		if err := p.UnmarshalBinary(append(valid, 0)); !errors.Is(err, ErrInvalidLength) {
+			t.Fatalf("expected %v, got %v", ErrInvalidLength, err)
+		}
+		if err := p.UnmarshalText([]byte("0xzz")); err == nil {
+			t.Fatalf("bad hex string should not be decoded")
+		}
+		if err := json.Unmarshal([]byte("1"), &p); err == nil {
+			t.Fatalf("non string json value should not be decoded")
+		}
+		fe := FpElement{}
+		if err := fe.UnmarshalBinary(modulus.Bytes()); !errors.Is(err, ErrNonCanonical) {
+			t.Fatalf("expected %v, got %v", ErrNonCanonical, err)
+		}
+		if fe != (FpElement{}) {
+			t.Fatalf("element should not be modified on failure")
+		}
+	})
+}
+
+func padHex(s string) string {
+	return string(bytes.Repeat([]byte("0"), 96-len(s))) + s
+}
@@ -0,0 +1,154 @@
+package plonk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/internal/gnark"
+	"github.com/kilic/bls12-381/kzg"
+)
+
+// Verifying keys, proofs and public witnesses are read in the binary format
+// written by gnark's WriteTo. Integers are big endian, field elements are 32
+// bytes big endian, points are compressed in zcash format and slices have a
+// uint32 length prefix.
+//
+// verifying key:
+//	uint64 size n, [1/n] [w] uint64 number of public inputs, [u] coset shift
+//	[S1]1 [S2]1 [S3]1 [Ql]1 [Qr]1 [Qm]1 [Qo]1 [Qk]1 uint32(len(Qcp)) [Qcp]1
+//	KZG [G1]1 [G2]2 [tau]2 and 2 * 2 * 63 precomputed pairing lines of 4 Fp
+//	elements in raw Montgomery form, uint32(len(indexes)) uint64 indexes
+//
+// proof:
+//	[L]1 [R]1 [O]1 [Z]1 [H1]1 [H2]1 [H3]1
+//	[W]1 uint32(len(values)) values of linearised polynomial, l, r, o, s1, s2
+//	at zeta, [Wz]1 z(w * zeta), uint32(len(commitments)) [commitments]1
+//
+// public witness:
+//	uint32(public) uint32(secret) uint32(len) inputs
+//
+// Keys and proofs with custom gate commitments are not supported.
+
+// pairingLinesSize is the size of precomputed pairing lines of KZG verifying
+// key which are skipped.
+const pairingLinesSize = 2 * 2 * 63 * 4 * 48
+
+// ReadVerifyingKey decodes verifying key written by gnark's
+// VerifyingKey.WriteTo.
+func ReadVerifyingKey(r io.Reader) (*VerifyingKey, error) {
+	d := gnark.NewReader(r)
+	vk := &VerifyingKey{}
+	var err error
+	if vk.Size, err = d.Uint64(); err != nil {
+		return nil, err
+	}
+	if vk.SizeInv, err = d.Scalar(); err != nil {
+		return nil, fmt.Errorf("size inverse: %w", err)
+	}
+	if vk.Generator, err = d.Scalar(); err != nil {
+		return nil, fmt.Errorf("generator: %w", err)
+	}
+	public, err := d.Uint64()
+	if err != nil {
+		return nil, err
+	}
+	if vk.CosetShift, err = d.Scalar(); err != nil {
+		return nil, fmt.Errorf("coset shift: %w", err)
+	}
+	if vk.Size == 0 || vk.Size&(vk.Size-1) != 0 || public > vk.Size {
+		return nil, fmt.Errorf("invalid domain size %d for %d public inputs", vk.Size, public)
+	}
+	vk.NumPublicInputs = int(public)
+	// 1 / n and w should match the domain
+	t := new(bls.Fr).SetUint64(vk.Size)
+	t.Mul(t, vk.SizeInv)
+	w := new(bls.Fr)
+	w.Exp(vk.Generator, new(big.Int).SetUint64(vk.Size))
+	if !t.IsOne() || !w.IsOne() {
+		return nil, errors.New("size inverse or generator does not match domain size")
+	}
+	for i, p := range []**bls.PointG1{&vk.S[0], &vk.S[1], &vk.S[2], &vk.Ql, &vk.Qr, &vk.Qm, &vk.Qo, &vk.Qk} {
+		if *p, err = d.G1Point(); err != nil {
+			return nil, fmt.Errorf("commitment %d: %w", i, err)
+		}
+	}
+	n, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	if n != 0 {
+		return nil, fmt.Errorf("%w: custom gate commitments", ErrUnsupported)
+	}
+	g1, err := d.G1Point()
+	if err != nil {
+		return nil, fmt.Errorf("KZG G1: %w", err)
+	}
+	g2, err := d.G2Point()
+	if err != nil {
+		return nil, fmt.Errorf("KZG G2: %w", err)
+	}
+	tau, err := d.G2Point()
+	if err != nil {
+		return nil, fmt.Errorf("KZG tau: %w", err)
+	}
+	if !bls.NewG1(bls.NewFp()).Equal(g1, &bls.G1One) || !bls.NewG2(bls.NewFp2(bls.NewFp())).Equal(g2, &bls.G2One) {
+		return nil, fmt.Errorf("%w: KZG setup with non standard generators", ErrUnsupported)
+	}
+	vk.SRS = &kzg.SRS{G1: []*bls.PointG1{g1}, G2: []*bls.PointG2{g2, tau}}
+	if err := d.Discard(pairingLinesSize); err != nil {
+		return nil, err
+	}
+	if n, err = d.Uint32(); err != nil {
+		return nil, err
+	}
+	if n != 0 || !d.End() {
+		return nil, fmt.Errorf("%w: custom gate commitments", ErrUnsupported)
+	}
+	return vk, nil
+}
+
+// ReadProof decodes proof written by gnark's Proof.WriteTo.
+func ReadProof(r io.Reader) (*Proof, error) {
+	d := gnark.NewReader(r)
+	proof := &Proof{}
+	var err error
+	for i, p := range []**bls.PointG1{&proof.LRO[0], &proof.LRO[1], &proof.LRO[2], &proof.Z, &proof.H[0], &proof.H[1], &proof.H[2], &proof.BatchedProof} {
+		if *p, err = d.G1Point(); err != nil {
+			return nil, fmt.Errorf("commitment %d: %w", i, err)
+		}
+	}
+	n, err := d.Uint32()
+	if err != nil {
+		return nil, err
+	}
+	if n != numClaimedValues {
+		return nil, fmt.Errorf("%w: %d claimed values", ErrUnsupported, n)
+	}
+	for i := range proof.ClaimedValues {
+		if proof.ClaimedValues[i], err = d.Scalar(); err != nil {
+			return nil, fmt.Errorf("claimed value %d: %w", i, err)
+		}
+	}
+	if proof.ZShiftedProof, err = d.G1Point(); err != nil {
+		return nil, fmt.Errorf("shifted opening: %w", err)
+	}
+	if proof.ZShiftedValue, err = d.Scalar(); err != nil {
+		return nil, fmt.Errorf("shifted value: %w", err)
+	}
+	if n, err = d.Uint32(); err != nil {
+		return nil, err
+	}
+	if n != 0 || !d.End() {
+		return nil, fmt.Errorf("%w: custom gate commitments", ErrUnsupported)
+	}
+	return proof, nil
+}
+
+// ReadPublicWitness decodes public witness written by gnark's
+// Witness.WriteTo.
+func ReadPublicWitness(r io.Reader) ([]*bls.Fr, error) {
+	return gnark.ReadPublicWitness(r)
+}
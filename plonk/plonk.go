@@ -0,0 +1,271 @@
+// Package plonk implements verifier of PLONK proofs over BLS12-381 with KZG
+// polynomial commitments. Proofs follow the variant of gnark with standard
+// gates ql * l + qr * r + qm * l * r + qo * o + qk = 0, a permutation
+// argument over three wires, quotient split into three parts and a SHA-256
+// Fiat-Shamir transcript.
+// https://eprint.iacr.org/2019/953.pdf
+package plonk
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"math/big"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/kzg"
+)
+
+// Errors returned by verification and decoding functions.
+var (
+	ErrPublicInputs = errors.New("number of public inputs does not match verifying key")
+	ErrUnsupported  = errors.New("unsupported verifying key or proof")
+)
+
+// numClaimedValues is the number of evaluations at zeta in a proof,
+// linearised polynomial, l, r, o, s1 and s2.
+const numClaimedValues = 6
+
+// VerifyingKey is a PLONK verifying key over a domain of Size roots of unity.
+type VerifyingKey struct {
+	Size            uint64
+	SizeInv         *bls.Fr
+	Generator       *bls.Fr
+	NumPublicInputs int
+	// CosetShift u separates wires in the permutation argument, l, r and o
+	// are indexed over w^i, u * w^i and u^2 * w^i.
+	CosetShift *bls.Fr
+	// S are commitments to permutation polynomials
+	S [3]*bls.PointG1
+	// Commitments to selector polynomials
+	Ql, Qr, Qm, Qo, Qk *bls.PointG1
+	// SRS holds KZG verifier setup, G1 generator and G2 powers
+	SRS *kzg.SRS
+}
+
+// Proof is a PLONK proof.
+type Proof struct {
+	// LRO are commitments to wire polynomials
+	LRO [3]*bls.PointG1
+	// Z is commitment to permutation accumulator
+	Z *bls.PointG1
+	// H are commitments to parts of quotient polynomial
+	H [3]*bls.PointG1
+	// BatchedProof opens linearised polynomial, l, r, o, s1 and s2 at zeta
+	// to ClaimedValues
+	BatchedProof  *bls.PointG1
+	ClaimedValues [numClaimedValues]*bls.Fr
+	// ZShiftedProof opens z at w * zeta to ZShiftedValue
+	ZShiftedProof *bls.PointG1
+	ZShiftedValue *bls.Fr
+}
+
+// Verify checks proof against public inputs. Challenges are derived from
+// the transcript, the linearised polynomial commitment is computed and
+// evaluations are checked with a batched KZG opening at zeta and w * zeta.
+func (vk *VerifyingKey) Verify(proof *Proof, public []*bls.Fr) (bool, error) {
+	if len(public) != vk.NumPublicInputs {
+		return false, ErrPublicInputs
+	}
+	g1 := bls.NewG1(bls.NewFp())
+
+	// gamma, beta, alpha and zeta
+	t := newTranscript(sha256.New(), "gamma", "beta", "alpha", "zeta")
+	for _, p := range []*bls.PointG1{vk.S[0], vk.S[1], vk.S[2], vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk} {
+		t.bind("gamma", g1.ToUncompressed(p))
+	}
+	for _, x := range public {
+		t.bind("gamma", x.ToBytes())
+	}
+	for _, p := range proof.LRO {
+		t.bind("gamma", g1.ToUncompressed(p))
+	}
+	gamma := t.challenge("gamma")
+	beta := t.challenge("beta")
+	t.bind("alpha", g1.ToUncompressed(proof.Z))
+	alpha := t.challenge("alpha")
+	for _, p := range proof.H {
+		t.bind("zeta", g1.ToUncompressed(p))
+	}
+	zeta := t.challenge("zeta")
+
+	one := new(bls.Fr).One()
+	// zh = zeta^n - 1, L_0(zeta) = (zeta^n - 1) / (n * (zeta - 1))
+	zetaN := new(bls.Fr)
+	zetaN.Exp(zeta, new(big.Int).SetUint64(vk.Size))
+	zh := new(bls.Fr)
+	zh.Sub(zetaN, one)
+	lagrangeZero := new(bls.Fr)
+	lagrangeZero.Sub(zeta, one)
+	lagrangeZero.Inverse(lagrangeZero)
+	lagrangeZero.Mul(lagrangeZero, zh)
+	lagrangeZero.Mul(lagrangeZero, vk.SizeInv)
+
+	// PI(zeta) = sum x_i * L_i(zeta) where L_i(zeta) = w^i / n * zh / (zeta - w^i)
+	pi, li, wi := new(bls.Fr), new(bls.Fr), new(bls.Fr).One()
+	for _, x := range public {
+		li.Sub(zeta, wi)
+		li.Inverse(li)
+		li.Mul(li, zh)
+		li.Mul(li, vk.SizeInv)
+		li.Mul(li, wi)
+		li.Mul(li, x)
+		pi.Add(pi, li)
+		wi.Mul(wi, vk.Generator)
+	}
+
+	lin, l, r, o, s1, s2 := proof.ClaimedValues[0], proof.ClaimedValues[1], proof.ClaimedValues[2],
+		proof.ClaimedValues[3], proof.ClaimedValues[4], proof.ClaimedValues[5]
+	zu := proof.ZShiftedValue
+
+	// alpha^2 * L_0(zeta)
+	alphaSquareLagrangeZero := new(bls.Fr)
+	alphaSquareLagrangeZero.Mul(lagrangeZero, alpha)
+	alphaSquareLagrangeZero.Mul(alphaSquareLagrangeZero, alpha)
+
+	// a = (l + beta * s1 + gamma) * (r + beta * s2 + gamma)
+	a, tmp := new(bls.Fr), new(bls.Fr)
+	a.Mul(beta, s1)
+	a.Add(a, gamma)
+	a.Add(a, l)
+	tmp.Mul(beta, s2)
+	tmp.Add(tmp, gamma)
+	tmp.Add(tmp, r)
+	a.Mul(a, tmp)
+
+	// constant term of linearisation must be equal to the opening of
+	// linearised polynomial,
+	// -(PI - alpha^2 * L_0 + alpha * a * (o + gamma) * z(w * zeta))
+	constLin := new(bls.Fr)
+	constLin.Add(o, gamma)
+	constLin.Mul(constLin, a)
+	constLin.Mul(constLin, alpha)
+	constLin.Mul(constLin, zu)
+	constLin.Sub(constLin, alphaSquareLagrangeZero)
+	constLin.Add(constLin, pi)
+	constLin.Neg(constLin)
+	if !constLin.Equal(lin) {
+		return false, nil
+	}
+
+	// commitment to linearised polynomial,
+	// l * Ql + r * Qr + l * r * Qm + o * Qo + Qk + c1 * S3 + c2 * Z
+	// - zh * (H1 + zeta^(n+2) * H2 + zeta^(2(n+2)) * H3)
+	// c1 = alpha * a * beta * z(w * zeta)
+	// c2 = alpha^2 * L_0 - alpha * (l + beta * zeta + gamma) *
+	// (r + beta * u * zeta + gamma) * (o + beta * u^2 * zeta + gamma)
+	c1 := new(bls.Fr)
+	c1.Mul(a, beta)
+	c1.Mul(c1, alpha)
+	c1.Mul(c1, zu)
+	c2, betaZeta := new(bls.Fr), new(bls.Fr)
+	betaZeta.Mul(beta, zeta)
+	c2.Add(betaZeta, gamma)
+	c2.Add(c2, l)
+	betaZeta.Mul(betaZeta, vk.CosetShift)
+	tmp.Add(betaZeta, gamma)
+	tmp.Add(tmp, r)
+	c2.Mul(c2, tmp)
+	betaZeta.Mul(betaZeta, vk.CosetShift)
+	tmp.Add(betaZeta, gamma)
+	tmp.Add(tmp, o)
+	c2.Mul(c2, tmp)
+	c2.Mul(c2, alpha)
+	c2.Sub(alphaSquareLagrangeZero, c2)
+	rl := new(bls.Fr)
+	rl.Mul(l, r)
+	h1, h2 := new(bls.Fr), new(bls.Fr)
+	h1.Exp(zeta, new(big.Int).SetUint64(vk.Size+2))
+	h2.Square(h1)
+	h1.Mul(h1, zh)
+	h1.Neg(h1)
+	h2.Mul(h2, zh)
+	h2.Neg(h2)
+	negZh := new(bls.Fr)
+	negZh.Neg(zh)
+	linDigest, err := g1.MultiExp(&bls.PointG1{},
+		[]*bls.PointG1{vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk, vk.S[2], proof.Z, proof.H[0], proof.H[1], proof.H[2]},
+		frsToBig([]*bls.Fr{l, r, rl, o, one, c1, c2, negZh, h1, h2}))
+	if err != nil {
+		return false, err
+	}
+
+	// fold openings at zeta with powers of a challenge
+	digests := []*bls.PointG1{linDigest, proof.LRO[0], proof.LRO[1], proof.LRO[2], vk.S[0], vk.S[1]}
+	ft := newTranscript(sha256.New(), "gamma")
+	ft.bind("gamma", zeta.ToBytes())
+	for _, p := range digests {
+		ft.bind("gamma", g1.ToUncompressed(p))
+	}
+	for _, v := range proof.ClaimedValues {
+		ft.bind("gamma", v.ToBytes())
+	}
+	ft.bind("gamma", zu.ToBytes())
+	foldingChallenge := ft.challenge("gamma")
+	powers := make([]*bls.Fr, len(digests))
+	foldedValue := new(bls.Fr)
+	for i := range powers {
+		powers[i] = new(bls.Fr).One()
+		if i > 0 {
+			powers[i].Mul(powers[i-1], foldingChallenge)
+		}
+		tmp.Mul(powers[i], proof.ClaimedValues[i])
+		foldedValue.Add(foldedValue, tmp)
+	}
+	foldedDigest, err := g1.MultiExp(&bls.PointG1{}, digests, frsToBig(powers))
+	if err != nil {
+		return false, err
+	}
+
+	shiftedZeta := new(bls.Fr)
+	shiftedZeta.Mul(zeta, vk.Generator)
+	return vk.SRS.BatchVerify(
+		[]*bls.PointG1{foldedDigest, proof.Z},
+		[]*bls.Fr{zeta, shiftedZeta},
+		[]*bls.Fr{foldedValue, zu},
+		[]*bls.PointG1{proof.BatchedProof, proof.ZShiftedProof},
+		nil,
+	)
+}
+
+// transcript derives challenges in a fixed order. A challenge is hash of its
+// name, previous challenge and values bound to it, reduced modulo curve order.
+type transcript struct {
+	h        hash.Hash
+	names    []string
+	bindings map[string][][]byte
+	previous []byte
+	next     int
+}
+
+func newTranscript(h hash.Hash, names ...string) *transcript {
+	return &transcript{h: h, names: names, bindings: make(map[string][][]byte)}
+}
+
+func (t *transcript) bind(name string, value []byte) {
+	t.bindings[name] = append(t.bindings[name], value)
+}
+
+// challenge computes the next challenge, which must be the given one.
+func (t *transcript) challenge(name string) *bls.Fr {
+	if t.next >= len(t.names) || t.names[t.next] != name {
+		panic("plonk: challenges out of order")
+	}
+	t.next++
+	t.h.Reset()
+	t.h.Write([]byte(name))
+	t.h.Write(t.previous)
+	for _, b := range t.bindings[name] {
+		t.h.Write(b)
+	}
+	t.previous = t.h.Sum(nil)
+	return new(bls.Fr).FromBytes(t.previous)
+}
+
+func frsToBig(in []*bls.Fr) []*big.Int {
+	out := make([]*big.Int, len(in))
+	for i := range in {
+		out[i] = in[i].ToBig()
+	}
+	return out
+}
@@ -0,0 +1,134 @@
+package plonk
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func readFile(t testing.TB, name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func open(t testing.TB, name string) io.Reader {
+	return bytes.NewReader(readFile(t, name))
+}
+
+func load(t testing.TB) (*VerifyingKey, []*Proof, [][]*bls.Fr) {
+	vk, err := ReadVerifyingKey(open(t, "verification_key.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var proofs []*Proof
+	var publics [][]*bls.Fr
+	for i := 0; i < 2; i++ {
+		proof, err := ReadProof(open(t, fmt.Sprintf("proof_%d.bin", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		public, err := ReadPublicWitness(open(t, fmt.Sprintf("public_%d.bin", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		proofs, publics = append(proofs, proof), append(publics, public)
+	}
+	return vk, proofs, publics
+}
+
+func TestVerify(t *testing.T) {
+	vk, proofs, publics := load(t)
+	t.Run("valid", func(t *testing.T) {
+		for i := range proofs {
+			ok, err := vk.Verify(proofs[i], publics[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				t.Fatalf("proof %d should be valid", i)
+			}
+		}
+	})
+	t.Run("wrong public inputs", func(t *testing.T) {
+		ok, err := vk.Verify(proofs[0], publics[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			t.Fatal("proof should not verify with other public inputs")
+		}
+		if _, err := vk.Verify(proofs[0], publics[0][:1]); err != ErrPublicInputs {
+			t.Fatalf("expected ErrPublicInputs, got %v", err)
+		}
+	})
+	t.Run("tampered proof", func(t *testing.T) {
+		for i := range proofs[0].ClaimedValues {
+			proof := *proofs[0]
+			v := new(bls.Fr).One()
+			v.Add(v, proof.ClaimedValues[i])
+			proof.ClaimedValues[i] = v
+			ok, err := vk.Verify(&proof, publics[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok {
+				t.Fatalf("proof with tampered claimed value %d should not verify", i)
+			}
+		}
+		proof := *proofs[0]
+		proof.ZShiftedProof = proofs[1].ZShiftedProof
+		if ok, _ := vk.Verify(&proof, publics[0]); ok {
+			t.Fatal("proof with swapped shifted opening should not verify")
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("truncated", func(t *testing.T) {
+		data := readFile(t, "verification_key.bin")
+		if _, err := ReadVerifyingKey(bytes.NewReader(data[:len(data)-1])); err == nil {
+			t.Fatal("truncated verifying key should fail")
+		}
+		data = readFile(t, "proof_0.bin")
+		if _, err := ReadProof(bytes.NewReader(data[:len(data)-1])); err == nil {
+			t.Fatal("truncated proof should fail")
+		}
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		data := append(readFile(t, "public_0.bin"), 0)
+		if _, err := ReadPublicWitness(bytes.NewReader(data)); err == nil {
+			t.Fatal("witness with trailing data should fail")
+		}
+		data = append(readFile(t, "proof_0.bin"), 0)
+		if _, err := ReadProof(bytes.NewReader(data)); err == nil {
+			t.Fatal("proof with trailing data should fail")
+		}
+	})
+	t.Run("non canonical scalar", func(t *testing.T) {
+		data := readFile(t, "public_0.bin")
+		for i := 12; i < 44; i++ {
+			data[i] = 0xff
+		}
+		if _, err := ReadPublicWitness(bytes.NewReader(data)); err == nil {
+			t.Fatal("non canonical public input should fail")
+		}
+	})
+}
+
+func BenchmarkVerify(b *testing.B) {
+	vk, proofs, publics := load(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, err := vk.Verify(proofs[0], publics[0]); !ok || err != nil {
+			b.Fatal("verification failed")
+		}
+	}
+}
@@ -0,0 +1,338 @@
+// Package poly implements dense univariate polynomials over scalar field of
+// BLS12-381. Polynomials are given with coefficients in increasing degree
+// order. Multiplication of large polynomials uses FFT.
+package poly
+
+import (
+	"errors"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/fft"
+)
+
+// Errors returned by polynomial functions.
+var (
+	ErrDivisionByZero = errors.New("division by zero polynomial")
+	ErrLength         = errors.New("points and evaluations should be in same length")
+	ErrNotDistinct    = errors.New("points should be distinct")
+)
+
+// fftThreshold is the size of the smaller operand above which multiplication
+// switches from schoolbook to FFT.
+const fftThreshold = 32
+
+// evalThreshold is the number of points below which multi point evaluation
+// evaluates one by one.
+const evalThreshold = 16
+
+// Polynomial is a dense polynomial, p[i] is coefficient of X^i.
+type Polynomial []*bls.Fr
+
+// Degree returns degree of the polynomial ignoring leading zeros, and -1 for
+// zero polynomial.
+func (p Polynomial) Degree() int {
+	for i := len(p) - 1; i >= 0; i-- {
+		if !p[i].IsZero() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clone returns a deep copy of the polynomial.
+func (p Polynomial) Clone() Polynomial {
+	out := make(Polynomial, len(p))
+	for i := range p {
+		out[i] = new(bls.Fr).Set(p[i])
+	}
+	return out
+}
+
+// Eval evaluates polynomial at x with Horner's method.
+func (p Polynomial) Eval(x *bls.Fr) *bls.Fr {
+	return bls.EvalPolynomial(p, x)
+}
+
+// trim drops leading zero coefficients.
+func (p Polynomial) trim() Polynomial {
+	return p[:p.Degree()+1]
+}
+
+func zeros(n int) Polynomial {
+	out := make(Polynomial, n)
+	for i := range out {
+		out[i] = new(bls.Fr)
+	}
+	return out
+}
+
+// Add returns a(X) + b(X).
+func Add(a, b Polynomial) Polynomial {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	out := a.Clone()
+	for i := range b {
+		out[i].Add(out[i], b[i])
+	}
+	return out
+}
+
+// Sub returns a(X) - b(X).
+func Sub(a, b Polynomial) Polynomial {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	out := zeros(n)
+	for i := range out {
+		if i < len(a) {
+			out[i].Set(a[i])
+		}
+		if i < len(b) {
+			out[i].Sub(out[i], b[i])
+		}
+	}
+	return out
+}
+
+// Scale returns c * a(X).
+func Scale(a Polynomial, c *bls.Fr) Polynomial {
+	out := make(Polynomial, len(a))
+	for i := range a {
+		out[i] = new(bls.Fr)
+		out[i].Mul(a[i], c)
+	}
+	return out
+}
+
+// Mul returns a(X) * b(X). Large inputs are multiplied with FFT, products
+// longer than 2^fft.MaxOrder coefficients fall back to schoolbook method.
+func Mul(a, b Polynomial) Polynomial {
+	if len(a) == 0 || len(b) == 0 {
+		return Polynomial{}
+	}
+	if len(a) < fftThreshold || len(b) < fftThreshold {
+		return mulSchoolbook(a, b)
+	}
+	n := len(a) + len(b) - 1
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	d, err := fft.NewDomain(size)
+	if err != nil {
+		return mulSchoolbook(a, b)
+	}
+	x, y := zeros(size), zeros(size)
+	for i := range a {
+		x[i].Set(a[i])
+	}
+	for i := range b {
+		y[i].Set(b[i])
+	}
+	d.FFT(x)
+	d.FFT(y)
+	for i := range x {
+		x[i].Mul(x[i], y[i])
+	}
+	d.InverseFFT(x)
+	return x[:n]
+}
+
+func mulSchoolbook(a, b Polynomial) Polynomial {
+	out := zeros(len(a) + len(b) - 1)
+	t := new(bls.Fr)
+	for i := range a {
+		for j := range b {
+			t.Mul(a[i], b[j])
+			out[i+j].Add(out[i+j], t)
+		}
+	}
+	return out
+}
+
+// DivideByLinear returns quotient of p(X) / (X - z) and the remainder p(z)
+// with synthetic division.
+func DivideByLinear(p Polynomial, z *bls.Fr) (Polynomial, *bls.Fr) {
+	if len(p) == 0 {
+		return Polynomial{}, new(bls.Fr)
+	}
+	q := make(Polynomial, len(p)-1)
+	r := new(bls.Fr).Set(p[len(p)-1])
+	for i := len(p) - 2; i >= 0; i-- {
+		q[i] = new(bls.Fr).Set(r)
+		r.Mul(r, z)
+		r.Add(r, p[i])
+	}
+	return q, r
+}
+
+// Divide returns quotient and remainder of a(X) / b(X) with long division.
+// Remainder has len(b) - 1 coefficients after leading zeros of b are dropped.
+func Divide(a, b Polynomial) (Polynomial, Polynomial, error) {
+	b = b.trim()
+	if len(b) == 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	if len(a) < len(b) {
+		r := zeros(len(b) - 1)
+		for i := range a {
+			r[i].Set(a[i])
+		}
+		return Polynomial{}, r, nil
+	}
+	r := a.Clone()
+	q := make(Polynomial, len(a)-len(b)+1)
+	inv := new(bls.Fr)
+	inv.Inverse(b[len(b)-1])
+	t := new(bls.Fr)
+	for i := len(q) - 1; i >= 0; i-- {
+		c := new(bls.Fr)
+		c.Mul(r[i+len(b)-1], inv)
+		q[i] = c
+		for j := range b {
+			t.Mul(c, b[j])
+			r[i+j].Sub(r[i+j], t)
+		}
+	}
+	return q, r[:len(b)-1], nil
+}
+
+// DivideByVanishing returns quotient and remainder of p(X) / (X^n - 1) where
+// X^n - 1 vanishes on the domain of n-th roots of unity. It runs in linear
+// time. n should be positive.
+func DivideByVanishing(p Polynomial, n int) (Polynomial, Polynomial, error) {
+	if n <= 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	if len(p) <= n {
+		r := zeros(n)
+		for i := range p {
+			r[i].Set(p[i])
+		}
+		return Polynomial{}, r, nil
+	}
+	// p = q * X^n - q + r, so q_i = p_{i+n} + q_{i+n} from top
+	r := p.Clone()
+	q := zeros(len(p) - n)
+	for i := len(p) - 1; i >= n; i-- {
+		q[i-n].Set(r[i])
+		r[i-n].Add(r[i-n], r[i])
+	}
+	return q, r[:n], nil
+}
+
+// Vanishing returns prod (X - z_i) with a product tree.
+func Vanishing(zs []*bls.Fr) Polynomial {
+	switch len(zs) {
+	case 0:
+		return Polynomial{new(bls.Fr).One()}
+	case 1:
+		p := Polynomial{new(bls.Fr), new(bls.Fr).One()}
+		p[0].Neg(zs[0])
+		return p
+	}
+	return Mul(Vanishing(zs[:len(zs)/2]), Vanishing(zs[len(zs)/2:]))
+}
+
+// Interpolate returns polynomial of degree less than number of points that
+// evaluates to ys at distinct points zs with Lagrange interpolation.
+func Interpolate(zs, ys []*bls.Fr) (Polynomial, error) {
+	if len(zs) != len(ys) {
+		return nil, ErrLength
+	}
+	out := zeros(len(zs))
+	vanishing := Vanishing(zs)
+	den, t := new(bls.Fr), new(bls.Fr)
+	for i := range zs {
+		// l_i(X) = Z(X) / (X - z_i) / prod_{j != i} (z_i - z_j)
+		num, _ := DivideByLinear(vanishing, zs[i])
+		den.One()
+		for j := range zs {
+			if i == j {
+				continue
+			}
+			t.Sub(zs[i], zs[j])
+			if t.IsZero() {
+				return nil, ErrNotDistinct
+			}
+			den.Mul(den, t)
+		}
+		den.Inverse(den)
+		den.Mul(den, ys[i])
+		for k := range num {
+			t.Mul(num[k], den)
+			out[k].Add(out[k], t)
+		}
+	}
+	return out, nil
+}
+
+// InterpolateDomain returns polynomial that evaluates to ys at roots of unity
+// of the domain, ys[i] = p(w^i).
+func InterpolateDomain(d *fft.Domain, ys []*bls.Fr) (Polynomial, error) {
+	p := Polynomial(ys).Clone()
+	if err := d.InverseFFT(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// EvalDomain evaluates polynomial at roots of unity of the domain. Polynomial
+// should have at most domain size coefficients.
+func EvalDomain(d *fft.Domain, p Polynomial) ([]*bls.Fr, error) {
+	if len(p) > d.Size {
+		return nil, fft.ErrInvalidSize
+	}
+	values := zeros(d.Size)
+	for i := range p {
+		values[i].Set(p[i])
+	}
+	if err := d.FFT(values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// EvalMulti evaluates polynomial at many points. Polynomial is reduced modulo
+// vanishing polynomials of halves of points down a product tree. Reductions
+// use long division, so it is not asymptotically faster than evaluating at
+// each point.
+func EvalMulti(p Polynomial, zs []*bls.Fr) []*bls.Fr {
+	out := make([]*bls.Fr, len(zs))
+	if len(zs) == 0 {
+		return out
+	}
+	evalTree(p, newProductTree(zs), out)
+	return out
+}
+
+// productTree holds vanishing polynomial of a range of points and subtrees of
+// its halves.
+type productTree struct {
+	zs          []*bls.Fr
+	vanishing   Polynomial
+	left, right *productTree
+}
+
+func newProductTree(zs []*bls.Fr) *productTree {
+	if len(zs) <= evalThreshold {
+		return &productTree{zs: zs, vanishing: Vanishing(zs)}
+	}
+	left, right := newProductTree(zs[:len(zs)/2]), newProductTree(zs[len(zs)/2:])
+	return &productTree{zs: zs, vanishing: Mul(left.vanishing, right.vanishing), left: left, right: right}
+}
+
+func evalTree(p Polynomial, t *productTree, out []*bls.Fr) {
+	// vanishing polynomial is monic so division never fails
+	_, r, _ := Divide(p, t.vanishing)
+	if t.left == nil {
+		for i, z := range t.zs {
+			out[i] = r.Eval(z)
+		}
+		return
+	}
+	evalTree(r, t.left, out[:len(t.left.zs)])
+	evalTree(r, t.right, out[len(t.left.zs):])
+}
@@ -0,0 +1,208 @@
+package poly
+
+import (
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+	"github.com/kilic/bls12-381/fft"
+)
+
+func randPolynomial(t testing.TB, n int) Polynomial {
+	p := make(Polynomial, n)
+	for i := range p {
+		e, err := new(bls.Fr).Rand(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p[i] = e
+	}
+	return p
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := randPolynomial(t, 9), randPolynomial(t, 4)
+	x := randPolynomial(t, 1)[0]
+	ax, bx := a.Eval(x), b.Eval(x)
+	e := new(bls.Fr)
+	e.Add(ax, bx)
+	if !Add(a, b).Eval(x).Equal(e) || !Add(b, a).Eval(x).Equal(e) {
+		t.Fatalf("bad addition")
+	}
+	e.Sub(ax, bx)
+	if !Sub(a, b).Eval(x).Equal(e) {
+		t.Fatalf("bad subtraction")
+	}
+	e.Mul(ax, bx)
+	if !Scale(a, bx).Eval(x).Equal(e) {
+		t.Fatalf("bad scaling")
+	}
+	if Sub(a, a).Degree() != -1 || a.Degree() != 8 {
+		t.Fatalf("bad degree")
+	}
+	// schoolbook and FFT multiplication
+	for _, n := range [][2]int{{9, 4}, {1, 40}, {100, 33}, {64, 64}} {
+		a, b := randPolynomial(t, n[0]), randPolynomial(t, n[1])
+		c := Mul(a, b)
+		if len(c) != n[0]+n[1]-1 {
+			t.Fatalf("bad product length")
+		}
+		e.Mul(a.Eval(x), b.Eval(x))
+		if !c.Eval(x).Equal(e) {
+			t.Fatalf("bad product of %d and %d coefficients", n[0], n[1])
+		}
+	}
+}
+
+func TestDivide(t *testing.T) {
+	x := randPolynomial(t, 1)[0]
+	check := func(a, q, b, r Polynomial) {
+		t.Helper()
+		left, right := a.Eval(x), q.Eval(x)
+		right.Mul(right, b.Eval(x))
+		right.Add(right, r.Eval(x))
+		if !left.Equal(right) {
+			t.Fatalf("a != q * b + r")
+		}
+	}
+	t.Run("Long", func(t *testing.T) {
+		a, b := randPolynomial(t, 9), randPolynomial(t, 4)
+		q, r, err := Divide(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(q) != 6 || len(r) != 3 {
+			t.Fatalf("bad quotient or remainder degree")
+		}
+		check(a, q, b, r)
+		// leading zeros of divisor are dropped
+		q, r, err = Divide(a, append(b.Clone(), new(bls.Fr)))
+		if err != nil || len(q) != 6 || len(r) != 3 {
+			t.Fatalf("leading zeros are not dropped")
+		}
+		q, r, err = Divide(b, a)
+		if err != nil || len(q) != 0 || len(r) != 8 {
+			t.Fatalf("bad division by larger polynomial")
+		}
+		check(b, q, a, r)
+		if _, _, err := Divide(a, Polynomial{new(bls.Fr)}); err != ErrDivisionByZero {
+			t.Fatalf("division by zero is accepted")
+		}
+	})
+	t.Run("Linear", func(t *testing.T) {
+		a, z := randPolynomial(t, 9), randPolynomial(t, 1)[0]
+		q, y := DivideByLinear(a, z)
+		if !y.Equal(a.Eval(z)) {
+			t.Fatalf("remainder is not evaluation")
+		}
+		b := Polynomial{new(bls.Fr), new(bls.Fr).One()}
+		b[0].Neg(z)
+		check(a, q, b, Polynomial{y})
+	})
+	t.Run("Vanishing", func(t *testing.T) {
+		n := 8
+		d, err := fft.NewDomain(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		z := Vanishing(d.Roots)
+		for _, size := range []int{5, 8, 20} {
+			a := randPolynomial(t, size)
+			q, r, err := DivideByVanishing(a, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r) != n {
+				t.Fatalf("bad remainder length")
+			}
+			check(a, q, z, r)
+			expectedQ, expectedR, _ := Divide(a, z)
+			if Sub(q, expectedQ).Degree() != -1 || Sub(r, expectedR).Degree() != -1 {
+				t.Fatalf("result differs from long division")
+			}
+		}
+		for _, n := range []int{0, -1} {
+			if _, _, err := DivideByVanishing(randPolynomial(t, 4), n); err != ErrDivisionByZero {
+				t.Fatalf("expected ErrDivisionByZero for n = %d, got %v", n, err)
+			}
+		}
+	})
+}
+
+func TestInterpolate(t *testing.T) {
+	zs := randPolynomial(t, 6)
+	ys := randPolynomial(t, 6)
+	p, err := Interpolate(zs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := Vanishing(zs)
+	if z.Degree() != 6 {
+		t.Fatalf("bad vanishing polynomial degree")
+	}
+	for j := range zs {
+		if !p.Eval(zs[j]).Equal(ys[j]) {
+			t.Fatalf("interpolation does not pass through point %d", j)
+		}
+		if !z.Eval(zs[j]).IsZero() {
+			t.Fatalf("vanishing polynomial is not zero at point %d", j)
+		}
+	}
+	if _, err := Interpolate(zs, ys[1:]); err != ErrLength {
+		t.Fatalf("inputs of different lengths are accepted")
+	}
+	zs[1] = zs[0]
+	if _, err := Interpolate(zs, ys); err != ErrNotDistinct {
+		t.Fatalf("duplicate points are accepted")
+	}
+	t.Run("Domain", func(t *testing.T) {
+		d, err := fft.NewDomain(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := randPolynomial(t, 10)
+		ys, err := EvalDomain(d, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range ys {
+			if !ys[i].Equal(p.Eval(d.Roots[i])) {
+				t.Fatalf("bad evaluation at %d", i)
+			}
+		}
+		q, err := InterpolateDomain(d, ys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Sub(p, q).Degree() != -1 {
+			t.Fatalf("interpolation does not round trip")
+		}
+		if _, err := EvalDomain(d, randPolynomial(t, 17)); err == nil {
+			t.Fatalf("polynomial larger than domain is accepted")
+		}
+	})
+}
+
+func TestEvalMulti(t *testing.T) {
+	p := randPolynomial(t, 100)
+	for _, n := range []int{0, 1, 16, 17, 70, 150} {
+		zs := randPolynomial(t, n)
+		ys := EvalMulti(p, zs)
+		if len(ys) != n {
+			t.Fatalf("bad number of evaluations")
+		}
+		for i := range zs {
+			if !ys[i].Equal(p.Eval(zs[i])) {
+				t.Fatalf("bad evaluation at %d of %d points", i, n)
+			}
+		}
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	x, y := randPolynomial(b, 4096), randPolynomial(b, 4096)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Mul(x, y)
+	}
+}
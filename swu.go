@@ -0,0 +1,125 @@
+package bls
+
+// swuMapG1 is implementation of Simplified Shallue-van de Woestijne-Ulas method
+// that maps a field element to a point on the 11-isogenous curve of G1.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-6.6.2
+func swuMapG1(f *Fp, u *Fe) (*Fe, *Fe) {
+	params := swuParamsForG1
+	var tv [2]Fe
+	f.Square(&tv[0], u)
+	f.Mul(&tv[0], &tv[0], params.z)
+	f.Square(&tv[1], &tv[0])
+	x1 := new(Fe)
+	f.Add(x1, &tv[0], &tv[1])
+	// x1 = B / (Z * A) in the exceptional case of RFC 9380
+	if x1.IsZero() {
+		x1.Set(params.minusZInv)
+	} else {
+		f.Inverse(x1, x1)
+		f.Add(x1, x1, &FpOne)
+	}
+	f.Mul(x1, x1, params.minusBOverA)
+	gx1 := new(Fe)
+	f.Square(gx1, x1)
+	f.Add(gx1, gx1, params.a)
+	f.Mul(gx1, gx1, x1)
+	f.Add(gx1, gx1, params.b)
+	x, y := new(Fe), new(Fe)
+	if f.IsSquare(gx1) {
+		x.Set(x1)
+		y.Set(gx1)
+	} else {
+		// x2 = z * u^2 * x1, g(x2) = (z * u^2)^3 * g(x1)
+		f.Mul(x, &tv[0], x1)
+		f.Mul(&tv[1], &tv[0], &tv[1])
+		f.Mul(y, gx1, &tv[1])
+	}
+	f.Sqrt(y, y)
+	if f.Sgn0(y) != f.Sgn0(u) {
+		f.Neg(y, y)
+	}
+	return x, y
+}
+
+// swuMapG2 is implementation of Simplified Shallue-van de Woestijne-Ulas method
+// that maps a field element to a point on the 3-isogenous curve of G2.
+// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-10#section-6.6.2
+func swuMapG2(fp *Fp2, u *Fe2) (*Fe2, *Fe2) {
+	params := swuParamsForG2
+	var tv [2]Fe2
+	fp.Square(&tv[0], u)
+	fp.Mul(&tv[0], &tv[0], params.z)
+	fp.Square(&tv[1], &tv[0])
+	x1 := new(Fe2)
+	fp.Add(x1, &tv[0], &tv[1])
+	// x1 = B / (Z * A) in the exceptional case of RFC 9380
+	if fp.IsZero(x1) {
+		fp.Copy(x1, params.minusZInv)
+	} else {
+		fp.Inverse(x1, x1)
+		fp.Add(x1, x1, &Fp2One)
+	}
+	fp.Mul(x1, x1, params.minusBOverA)
+	gx1 := new(Fe2)
+	fp.Square(gx1, x1)
+	fp.Add(gx1, gx1, params.a)
+	fp.Mul(gx1, gx1, x1)
+	fp.Add(gx1, gx1, params.b)
+	x, y := new(Fe2), new(Fe2)
+	if fp.IsSquare(gx1) {
+		fp.Copy(x, x1)
+		fp.Copy(y, gx1)
+	} else {
+		fp.Mul(x, &tv[0], x1)
+		fp.Mul(&tv[1], &tv[0], &tv[1])
+		fp.Mul(y, gx1, &tv[1])
+	}
+	fp.Sqrt(y, y)
+	if fp.Sgn0(y) != fp.Sgn0(u) {
+		fp.Neg(y, y)
+	}
+	return x, y
+}
+
+var swuParamsForG1 = struct {
+	z           *Fe
+	minusZInv   *Fe // -1 / z
+	a           *Fe
+	b           *Fe
+	minusBOverA *Fe
+}{
+	a:           &Fe{0x2f65aa0e9af5aa51, 0x86464c2d1e8416c3, 0xb85ce591b7bd31e2, 0x27e11c91b5f24e7c, 0x28376eda6bfc1835, 0x155455c3e5071d85},
+	b:           &Fe{0xfb996971fe22a1e0, 0x9aa93eb35b742d6f, 0x8c476013de99c5c4, 0x873e27c3a221e571, 0xca72b5e45a52d888, 0x06824061418a386b},
+	z:           &Fe{0x886c00000023ffdc, 0x0f70008d3090001d, 0x77672417ed5828c3, 0x9dac23e943dc1740, 0x50553f1b9c131521, 0x078c712fbe0ab6e8},
+	minusZInv:   &Fe{0x0e8a2e8ba2e83e10, 0x5b28ba2ca4d745d1, 0x678cd5473847377a, 0x4c506dd8a8076116, 0x9bcb227d79284139, 0x0e8d3154b0ba099a},
+	minusBOverA: &Fe{0x052583c93555a7fe, 0x3b40d72430f93c82, 0x1b75faa0105ec983, 0x2527e7dc63851767, 0x99fffd1f34fc181d, 0x097cab54770ca0d3},
+}
+
+var swuParamsForG2 = struct {
+	z           *Fe2
+	minusZInv   *Fe2 // -1 / z
+	a           *Fe2
+	b           *Fe2
+	minusBOverA *Fe2
+}{
+	a: &Fe2{
+		Fe{0, 0, 0, 0, 0, 0},
+		Fe{0xe53a000003135242, 0x01080c0fdef80285, 0xe7889edbe340f6bd, 0x0b51375126310601, 0x02d6985717c744ab, 0x1220b4e979ea5467},
+	},
+	b: &Fe2{
+		Fe{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
+		Fe{0x22ea00000cf89db2, 0x6ec832df71380aa4, 0x6e1b94403db5a66e, 0x75bf3c53a79473ba, 0x3dd3a569412c0a34, 0x125cdb5e74dc4fd1},
+	},
+	z: &Fe2{
+		Fe{0x87ebfffffff9555c, 0x656fffe5da8ffffa, 0x0fd0749345d33ad2, 0xd951e663066576f4, 0xde291a3d41e980d3, 0x0815664c7dfe040d},
+		Fe{0x43f5fffffffcaaae, 0x32b7fff2ed47fffd, 0x07e83a49a2e99d69, 0xeca8f3318332bb7a, 0xef148d1ea0f4c069, 0x040ab3263eff0206},
+	},
+	minusZInv: &Fe2{
+		Fe{0xacd0000000011110, 0x9dd9999dc88ccccd, 0xb5ca2ac9b76352bf, 0xf1b574bcf4bc90ce, 0x42dab41f28a77081, 0x132fc6ac14cd1e12},
+		Fe{0xe396ffffffff2223, 0x4fbf332fcd0d9998, 0x0c4bbd3c1aff4cc4, 0x6b9c91267926ca58, 0x29ae4da6aef7f496, 0x10692e942f195791},
+	},
+	minusBOverA: &Fe2{
+		Fe{0x903c555555474fb3, 0x5f98cc95ce451105, 0x9f8e582eefe0fade, 0xc68946b6aebbd062, 0x467a4ad10ee6de53, 0x0e7146f483e23a05},
+		Fe{0x29c2aaaaaab85af8, 0xbf133368e30eeefa, 0xc7a27a7206cffb45, 0x9dee04ce44c9425c, 0x04a15ce53464ce83, 0x0b8fcaf5b59dac95},
+	},
+}
@@ -0,0 +1,149 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// SplitSecret splits secret into n shares so that any t of them recover the
+// secret. Share at position i belongs to participant with index i+1, that is,
+// evaluation of a random polynomial of degree t-1 whose constant term is
+// secret.
+func SplitSecret(secret *Fr, t, n int, r io.Reader) ([]*Fr, error) {
+	if t < 1 || t > n {
+		return nil, fmt.Errorf("threshold should be in range of [1, %d]", n)
+	}
+	coeffs, err := randomPolynomial(secret, t, r)
+	if err != nil {
+		return nil, err
+	}
+	shares := make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		shares[i] = evalPolynomial(coeffs, uint64(i+1))
+	}
+	return shares, nil
+}
+
+// RecoverSecret interpolates shares at given participant indexes at zero.
+func RecoverSecret(shares []*Fr, indexes []uint64) (*Fr, error) {
+	if len(shares) != len(indexes) {
+		return nil, fmt.Errorf("%w: shares and indexes should be in same length", ErrInvalidLength)
+	}
+	lambdas, err := lagrangeCoefficientsAtZero(indexes)
+	if err != nil {
+		return nil, err
+	}
+	secret, t := new(Fr), new(Fr)
+	for i := range shares {
+		t.Mul(shares[i], lambdas[i])
+		secret.Add(secret, t)
+	}
+	return secret, nil
+}
+
+// RecoverSignature combines partial signatures in G2 signed by participants
+// with given indexes into the signature of the shared secret.
+func RecoverSignature(partials []*PointG2, indexes []uint64) (*PointG2, error) {
+	if len(partials) != len(indexes) {
+		return nil, fmt.Errorf("%w: partials and indexes should be in same length", ErrInvalidLength)
+	}
+	lambdas, err := lagrangeCoefficientsAtZero(indexes)
+	if err != nil {
+		return nil, err
+	}
+	g := NewG2(NewFp2(NewFp()))
+	return g.MultiExp(g.Zero(), partials, frsToBig(lambdas))
+}
+
+// RecoverPublicKey combines public key shares in G1 of participants with
+// given indexes into the public key of the shared secret.
+func RecoverPublicKey(partials []*PointG1, indexes []uint64) (*PointG1, error) {
+	if len(partials) != len(indexes) {
+		return nil, fmt.Errorf("%w: partials and indexes should be in same length", ErrInvalidLength)
+	}
+	lambdas, err := lagrangeCoefficientsAtZero(indexes)
+	if err != nil {
+		return nil, err
+	}
+	g := NewG1(NewFp())
+	return g.MultiExp(g.Zero(), partials, frsToBig(lambdas))
+}
+
+// randomPolynomial returns coefficients of a random polynomial of degree t-1
+// with given constant term.
+func randomPolynomial(constant *Fr, t int, r io.Reader) ([]*Fr, error) {
+	coeffs := make([]*Fr, t)
+	coeffs[0] = new(Fr).Set(constant)
+	for i := 1; i < t; i++ {
+		c, err := new(Fr).Rand(r)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = c
+	}
+	return coeffs, nil
+}
+
+// EvalPolynomial evaluates polynomial with coefficients in ascending degree
+// at x with horner's method. Empty polynomial evaluates to zero.
+func EvalPolynomial(coeffs []*Fr, x *Fr) *Fr {
+	s := new(Fr)
+	for j := len(coeffs) - 1; j >= 0; j-- {
+		s.Mul(s, x)
+		s.Add(s, coeffs[j])
+	}
+	return s
+}
+
+// evalPolynomial evaluates polynomial at participant index x.
+func evalPolynomial(coeffs []*Fr, x uint64) *Fr {
+	return EvalPolynomial(coeffs, new(Fr).SetUint64(x))
+}
+
+// lagrangeCoefficientsAtZero returns l_i(0) = prod_{j != i} x_j / (x_j - x_i)
+// for distinct non zero indexes.
+func lagrangeCoefficientsAtZero(indexes []uint64) ([]*Fr, error) {
+	if len(indexes) == 0 {
+		return nil, errors.New("at least one index is required")
+	}
+	xs := make([]Fr, len(indexes))
+	seen := make(map[uint64]bool, len(indexes))
+	for i, index := range indexes {
+		if index == 0 {
+			return nil, errors.New("index should be non zero")
+		}
+		if seen[index] {
+			return nil, fmt.Errorf("duplicate index %d", index)
+		}
+		seen[index] = true
+		xs[i].SetUint64(index)
+	}
+	lambdas := make([]*Fr, len(indexes))
+	num, den, t := new(Fr), new(Fr), new(Fr)
+	for i := range xs {
+		num.One()
+		den.One()
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num.Mul(num, &xs[j])
+			t.Sub(&xs[j], &xs[i])
+			den.Mul(den, t)
+		}
+		den.Inverse(den)
+		lambdas[i] = new(Fr)
+		lambdas[i].Mul(num, den)
+	}
+	return lambdas, nil
+}
+
+func frsToBig(in []*Fr) []*big.Int {
+	out := make([]*big.Int, len(in))
+	for i := range in {
+		out[i] = in[i].ToBig()
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package bls
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestThreshold(t *testing.T) {
+	g1 := NewG1(NewFp())
+	g2 := NewG2(NewFp2(NewFp()))
+	tt, nn := 3, 5
+	secret, err := new(Fr).Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := SplitSecret(secret, tt, nn, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// message hash is a random point in G2
+	h := g2.MulScalar(&PointG2{}, &G2One, randScalar(q))
+	publicKey := g1.MulScalar(&PointG1{}, &G1One, secret.ToBig())
+	signature := g2.MulScalar(&PointG2{}, h, secret.ToBig())
+	partials := make([]*PointG2, nn)
+	publicKeys := make([]*PointG1, nn)
+	for i, share := range shares {
+		partials[i] = g2.MulScalar(&PointG2{}, h, share.ToBig())
+		publicKeys[i] = g1.MulScalar(&PointG1{}, &G1One, share.ToBig())
+	}
+	t.Run("Recover", func(t *testing.T) {
+		for _, indexes := range [][]uint64{{1, 2, 3}, {5, 3, 1}, {2, 4, 5, 1}, {1, 2, 3, 4, 5}} {
+			subShares := make([]*Fr, len(indexes))
+			subPartials := make([]*PointG2, len(indexes))
+			subPublicKeys := make([]*PointG1, len(indexes))
+			for i, index := range indexes {
+				subShares[i] = shares[index-1]
+				subPartials[i] = partials[index-1]
+				subPublicKeys[i] = publicKeys[index-1]
+			}
+			s, err := RecoverSecret(subShares, indexes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !s.Equal(secret) {
+				t.Fatalf("bad secret recovery with %v", indexes)
+			}
+			sig, err := RecoverSignature(subPartials, indexes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g2.Equal(sig, signature) {
+				t.Fatalf("bad signature recovery with %v", indexes)
+			}
+			pk, err := RecoverPublicKey(subPublicKeys, indexes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g1.Equal(pk, publicKey) {
+				t.Fatalf("bad public key recovery with %v", indexes)
+			}
+		}
+	})
+	t.Run("BelowThreshold", func(t *testing.T) {
+		sig, err := RecoverSignature(partials[:2], []uint64{1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g2.Equal(sig, signature) {
+			t.Fatalf("signature should not be recovered with less than threshold shares")
+		}
+	})
+	t.Run("BadInput", func(t *testing.T) {
+		if _, err := SplitSecret(secret, nn+1, nn, rand.Reader); err == nil {
+			t.Fatalf("threshold larger than number of shares should be rejected")
+		}
+		if _, err := RecoverSignature(partials[:2], []uint64{1}); err == nil {
+			t.Fatalf("length mismatch should be rejected")
+		}
+		if _, err := RecoverSignature(partials[:2], []uint64{1, 1}); err == nil {
+			t.Fatalf("duplicate index should be rejected")
+		}
+		if _, err := RecoverPublicKey(publicKeys[:2], []uint64{0, 1}); err == nil {
+			t.Fatalf("zero index should be rejected")
+		}
+	})
+}
+
+func TestEvalPolynomial(t *testing.T) {
+	x := new(Fr).SetUint64(2)
+	if !EvalPolynomial(nil, x).IsZero() {
+		t.Fatalf("empty polynomial should evaluate to zero")
+	}
+	// 1 + 2x + 3x^2 at 2
+	coeffs := []*Fr{new(Fr).SetUint64(1), new(Fr).SetUint64(2), new(Fr).SetUint64(3)}
+	if !EvalPolynomial(coeffs, x).Equal(new(Fr).SetUint64(17)) {
+		t.Fatalf("bad evaluation")
+	}
+}
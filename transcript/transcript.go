@@ -0,0 +1,163 @@
+// Package transcript implements a Fiat-Shamir transcript in the spirit of
+// Merlin. Prover and verifier append the same labeled values in the same
+// order and derive identical challenges, each challenge depends on every
+// value absorbed before it.
+//
+// Values are absorbed in their canonical encodings, scalars as 32 bytes big
+// endian, Fp elements as 48 bytes big endian, G1 and G2 points compressed
+// and GT elements with Fp12.ToBytes. Each operation is framed as
+//
+//	op || uint32(len(label)) || label || uint32(len(data)) || data
+//
+// with big endian lengths and absorbed into SHAKE256. Challenges are read
+// from a copy of the state after the challenge frame is absorbed. The format
+// is not compatible with Merlin.
+// https://merlin.cool
+//
+// Protocols whose transcripts are fixed by an external specification, such
+// as EIP-4844 or gnark PLONK proofs, keep their own challenge derivation.
+package transcript
+
+import (
+	"encoding/binary"
+
+	bls "github.com/kilic/bls12-381"
+	"golang.org/x/crypto/sha3"
+)
+
+// protocolLabel separates transcripts of this package from other uses of
+// SHAKE256.
+var protocolLabel = []byte("BLS12381-TRANSCRIPT-V1")
+
+const (
+	opAppend    byte = 1
+	opChallenge byte = 2
+)
+
+// scalarChallengeSize is the number of bytes reduced to a scalar challenge,
+// large enough to make the bias negligible.
+const scalarChallengeSize = 64
+
+// Transcript is a Fiat-Shamir transcript. It is not safe for concurrent use.
+type Transcript struct {
+	h    sha3.ShakeHash
+	fp   *bls.Fp
+	g1   *bls.G1
+	g2   *bls.G2
+	fp12 *bls.Fp12
+}
+
+// New creates a transcript separated by a protocol label.
+func New(label []byte) *Transcript {
+	t := newTranscript(sha3.NewShake256())
+	t.absorb(opAppend, protocolLabel, label)
+	return t
+}
+
+func newTranscript(h sha3.ShakeHash) *Transcript {
+	fp := bls.NewFp()
+	fp2 := bls.NewFp2(fp)
+	return &Transcript{
+		h:    h,
+		fp:   fp,
+		g1:   bls.NewG1(fp),
+		g2:   bls.NewG2(fp2),
+		fp12: bls.NewFp12(bls.NewFp6(fp2)),
+	}
+}
+
+// Clone returns an independent copy of the transcript, for instance to fork
+// it into sub protocols.
+func (t *Transcript) Clone() *Transcript {
+	return newTranscript(t.h.Clone())
+}
+
+func (t *Transcript) absorb(op byte, label, data []byte) {
+	var n [4]byte
+	t.h.Write([]byte{op})
+	binary.BigEndian.PutUint32(n[:], uint32(len(label)))
+	t.h.Write(n[:])
+	t.h.Write(label)
+	binary.BigEndian.PutUint32(n[:], uint32(len(data)))
+	t.h.Write(n[:])
+	t.h.Write(data)
+}
+
+// AppendMessage absorbs a labeled message.
+func (t *Transcript) AppendMessage(label, msg []byte) {
+	t.absorb(opAppend, label, msg)
+}
+
+// AppendScalar absorbs a scalar.
+func (t *Transcript) AppendScalar(label []byte, e *bls.Fr) {
+	t.absorb(opAppend, label, e.ToBytes())
+}
+
+// AppendScalars absorbs scalars in order.
+func (t *Transcript) AppendScalars(label []byte, es []*bls.Fr) {
+	for _, e := range es {
+		t.AppendScalar(label, e)
+	}
+}
+
+// AppendFe absorbs a base field element.
+func (t *Transcript) AppendFe(label []byte, fe *bls.Fe) {
+	t.absorb(opAppend, label, t.fp.ToBytes(fe))
+}
+
+// AppendG1 absorbs a G1 point in compressed form. Representation of the
+// point does not affect the transcript and p is not modified.
+func (t *Transcript) AppendG1(label []byte, p *bls.PointG1) {
+	t.absorb(opAppend, label, t.g1.ToCompressed(new(bls.PointG1).Set(p)))
+}
+
+// AppendG1s absorbs G1 points in order.
+func (t *Transcript) AppendG1s(label []byte, ps []*bls.PointG1) {
+	for _, p := range ps {
+		t.AppendG1(label, p)
+	}
+}
+
+// AppendG2 absorbs a G2 point in compressed form. Representation of the
+// point does not affect the transcript and p is not modified.
+func (t *Transcript) AppendG2(label []byte, p *bls.PointG2) {
+	t.absorb(opAppend, label, t.g2.ToCompressed(new(bls.PointG2).Set(p)))
+}
+
+// AppendG2s absorbs G2 points in order.
+func (t *Transcript) AppendG2s(label []byte, ps []*bls.PointG2) {
+	for _, p := range ps {
+		t.AppendG2(label, p)
+	}
+}
+
+// AppendGT absorbs a target group element such as a pairing result.
+func (t *Transcript) AppendGT(label []byte, e *bls.Fe12) {
+	t.absorb(opAppend, label, t.fp12.ToBytes(e))
+}
+
+// ChallengeBytes derives n bytes of challenge. The challenge frame is
+// absorbed, so later challenges depend on this one. The length is bound to
+// the challenge as a 4 byte frame, hence its type.
+func (t *Transcript) ChallengeBytes(label []byte, n uint32) []byte {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], n)
+	t.absorb(opChallenge, label, size[:])
+	out := make([]byte, n)
+	t.h.Clone().Read(out)
+	return out
+}
+
+// ChallengeScalar derives a uniformly distributed scalar challenge.
+func (t *Transcript) ChallengeScalar(label []byte) *bls.Fr {
+	return new(bls.Fr).FromBytes(t.ChallengeBytes(label, scalarChallengeSize))
+}
+
+// ChallengeScalars derives n scalar challenges under the same label.
+func (t *Transcript) ChallengeScalars(label []byte, n int) []*bls.Fr {
+	out := make([]*bls.Fr, n)
+	for i := range out {
+		out[i] = t.ChallengeScalar(label)
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package transcript
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	bls "github.com/kilic/bls12-381"
+)
+
+func challenge(f func(tr *Transcript)) *bls.Fr {
+	tr := New([]byte("test"))
+	f(tr)
+	return tr.ChallengeScalar([]byte("c"))
+}
+
+func randScalar(t *testing.T) *bls.Fr {
+	x, err := new(bls.Fr).Rand(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func TestTranscript(t *testing.T) {
+	t.Run("regression vector", func(t *testing.T) {
+		// expected challenge is produced by this implementation and guards
+		// the format against changes, it is not an external test vector
+		c := challenge(func(tr *Transcript) {
+			tr.AppendMessage([]byte("msg"), []byte("hello"))
+			tr.AppendScalar([]byte("x"), new(bls.Fr).SetUint64(42))
+			tr.AppendG1([]byte("g1"), &bls.G1One)
+			tr.AppendG2([]byte("g2"), &bls.G2One)
+		})
+		expected := "0x584b4155c6a65464d51fe25fdfcee2c1a90998d0e2a4aeacf87be660b5639bb9"
+		if c.String() != expected {
+			t.Fatalf("challenge %s, expected %s", c, expected)
+		}
+	})
+	t.Run("separation", func(t *testing.T) {
+		c := challenge(func(tr *Transcript) { tr.AppendMessage([]byte("a"), []byte("bc")) })
+		variants := []func(tr *Transcript){
+			func(tr *Transcript) {},
+			// boundary of label and data is framed
+			func(tr *Transcript) { tr.AppendMessage([]byte("ab"), []byte("c")) },
+			// split values are distinguished
+			func(tr *Transcript) {
+				tr.AppendMessage([]byte("a"), []byte("b"))
+				tr.AppendMessage([]byte("a"), []byte("c"))
+			},
+		}
+		for i, f := range variants {
+			if c.Equal(challenge(f)) {
+				t.Fatalf("variant %d should give different challenge", i)
+			}
+		}
+		other := New([]byte("other"))
+		other.AppendMessage([]byte("a"), []byte("bc"))
+		if c.Equal(other.ChallengeScalar([]byte("c"))) {
+			t.Fatal("protocol label should separate transcripts")
+		}
+		tr := New([]byte("test"))
+		tr.AppendMessage([]byte("a"), []byte("bc"))
+		if c.Equal(tr.ChallengeScalar([]byte("d"))) {
+			t.Fatal("challenge label should separate challenges")
+		}
+	})
+	t.Run("successive challenges", func(t *testing.T) {
+		cs := New([]byte("test")).ChallengeScalars([]byte("c"), 3)
+		if cs[0].Equal(cs[1]) || cs[1].Equal(cs[2]) {
+			t.Fatal("successive challenges should differ")
+		}
+		short := New([]byte("test")).ChallengeBytes([]byte("c"), 32)
+		long := New([]byte("test")).ChallengeBytes([]byte("c"), 64)
+		if bytes.Equal(short, long[:32]) {
+			t.Fatal("challenge length should be bound")
+		}
+	})
+	t.Run("clone", func(t *testing.T) {
+		tr := New([]byte("test"))
+		tr.AppendMessage([]byte("a"), []byte("b"))
+		fork := tr.Clone()
+		fork.AppendMessage([]byte("d"), []byte("e"))
+		c := tr.Clone().ChallengeScalar([]byte("c"))
+		if !c.Equal(tr.ChallengeScalar([]byte("c"))) {
+			t.Fatal("clone should give same challenge")
+		}
+		if c.Equal(fork.ChallengeScalar([]byte("c"))) {
+			t.Fatal("fork should diverge")
+		}
+	})
+}
+
+func TestCanonicalEncoding(t *testing.T) {
+	t.Run("G1", func(t *testing.T) {
+		g1 := bls.NewG1(bls.NewFp())
+		// projective representation with z != 1
+		p := g1.MulScalar(&bls.PointG1{}, &bls.G1One, randScalar(t).ToBig())
+		p = g1.Double(&bls.PointG1{}, p)
+		z := p[2]
+		c := challenge(func(tr *Transcript) { tr.AppendG1([]byte("p"), p) })
+		if p[2] != z {
+			t.Fatal("point should not be modified")
+		}
+		affine := new(bls.PointG1).Set(p)
+		g1.Affine(affine)
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendG1s([]byte("p"), []*bls.PointG1{affine}) })) {
+			t.Fatal("representation should not affect transcript")
+		}
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendMessage([]byte("p"), g1.ToCompressed(affine)) })) {
+			t.Fatal("G1 should be absorbed compressed")
+		}
+	})
+	t.Run("G2", func(t *testing.T) {
+		g2 := bls.NewG2(bls.NewFp2(bls.NewFp()))
+		p := g2.MulScalar(&bls.PointG2{}, &bls.G2One, randScalar(t).ToBig())
+		c := challenge(func(tr *Transcript) { tr.AppendG2s([]byte("p"), []*bls.PointG2{p}) })
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendMessage([]byte("p"), g2.ToCompressed(p)) })) {
+			t.Fatal("G2 should be absorbed compressed")
+		}
+	})
+	t.Run("GT", func(t *testing.T) {
+		e := bls.NewBLSPairingEngine()
+		gt := e.Fp12.Zero()
+		e.Pair(gt, []bls.PointG1{bls.G1One}, []bls.PointG2{bls.G2One})
+		c := challenge(func(tr *Transcript) { tr.AppendGT([]byte("gt"), gt) })
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendMessage([]byte("gt"), e.Fp12.ToBytes(gt)) })) {
+			t.Fatal("GT should be absorbed with Fp12.ToBytes")
+		}
+	})
+	t.Run("scalar", func(t *testing.T) {
+		x := randScalar(t)
+		c := challenge(func(tr *Transcript) { tr.AppendScalars([]byte("x"), []*bls.Fr{x}) })
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendMessage([]byte("x"), x.ToBytes()) })) {
+			t.Fatal("scalar should be absorbed as 32 bytes")
+		}
+	})
+	t.Run("Fe", func(t *testing.T) {
+		fp := bls.NewFp()
+		fe, err := fp.RandElement(&bls.Fe{}, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := challenge(func(tr *Transcript) { tr.AppendFe([]byte("fe"), fe) })
+		if !c.Equal(challenge(func(tr *Transcript) { tr.AppendMessage([]byte("fe"), fp.ToBytes(fe)) })) {
+			t.Fatal("Fe should be absorbed as 48 bytes")
+		}
+	})
+}
+
+func BenchmarkChallengeScalar(b *testing.B) {
+	tr := New([]byte("bench"))
+	label := []byte("c")
+	for i := 0; i < b.N; i++ {
+		tr.ChallengeScalar(label)
+	}
+}
@@ -3,6 +3,8 @@ package bls
 import (
 	"encoding/hex"
 	"fmt"
+	"math/big"
+	"math/bits"
 )
 
 func bytes_(size int, hexStrs ...string) []byte {
@@ -34,3 +36,17 @@ func bytes_(size int, hexStrs ...string) []byte {
 	}
 	return out
 }
+
+// window returns c bits of s starting from bit i.
+func window(s *big.Int, i, c int) int {
+	words := s.Bits()
+	var w int
+	for j := c - 1; j >= 0; j-- {
+		k := i + j
+		w <<= 1
+		if k/bits.UintSize < len(words) {
+			w |= int(words[k/bits.UintSize]>>uint(k%bits.UintSize)) & 1
+		}
+	}
+	return w
+}
@@ -0,0 +1,214 @@
+package bls
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+)
+
+// Domain separation tags used to derive second generators of Pedersen
+// commitments.
+var (
+	pedersenDST   = []byte("BLS12381G1_XMD:SHA-256_SSWU_RO_PEDERSEN_VSS_")
+	pedersenDSTG2 = []byte("BLS12381G2_XMD:SHA-256_SSWU_RO_PEDERSEN_VSS_")
+)
+
+// Second generators are derived once on first use.
+var (
+	pedersenOnce, pedersenOnceG2 sync.Once
+	pedersenH                    *PointG1
+	pedersenHG2                  *PointG2
+)
+
+// PedersenGenerator returns the second generator H of Pedersen commitments.
+// H is obtained by hashing to G1 so that its discrete logarithm with respect
+// to G1 generator is unknown.
+func PedersenGenerator() *PointG1 {
+	pedersenOnce.Do(func() {
+		// hashing fails only for invalid output lengths which are constant
+		pedersenH, _ = NewG1(NewFp()).HashToCurve([]byte("H"), pedersenDST)
+	})
+	return new(PointG1).Set(pedersenH)
+}
+
+// PedersenGeneratorG2 returns the second generator H of Pedersen commitments
+// in G2, obtained by hashing to G2.
+func PedersenGeneratorG2() *PointG2 {
+	pedersenOnceG2.Do(func() {
+		// hashing fails only for invalid output lengths which are constant
+		pedersenHG2, _ = NewG2(NewFp2(NewFp())).HashToCurve([]byte("H"), pedersenDSTG2)
+	})
+	return new(PointG2).Set(pedersenHG2)
+}
+
+// FeldmanShare splits secret into n shares with threshold t as SplitSecret
+// does and also returns commitments to coefficients of the sharing polynomial
+// a_j * G where G is G1 generator. First commitment is the public key of the
+// secret.
+func FeldmanShare(secret *Fr, t, n int, r io.Reader) ([]*Fr, []*PointG1, error) {
+	coeffs, shares, err := sharePolynomial(secret, t, n, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := NewG1(NewFp())
+	commitments := make([]*PointG1, t)
+	for j := range coeffs {
+		commitments[j] = g.MulScalar(&PointG1{}, &G1One, coeffs[j].ToBig())
+	}
+	return shares, commitments, nil
+}
+
+// FeldmanShareG2 is FeldmanShare with commitments in G2.
+func FeldmanShareG2(secret *Fr, t, n int, r io.Reader) ([]*Fr, []*PointG2, error) {
+	coeffs, shares, err := sharePolynomial(secret, t, n, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	g := NewG2(NewFp2(NewFp()))
+	commitments := make([]*PointG2, t)
+	for j := range coeffs {
+		commitments[j] = g.MulScalar(&PointG2{}, &G2One, coeffs[j].ToBig())
+	}
+	return shares, commitments, nil
+}
+
+// FeldmanVerify checks share of participant at given index against Feldman
+// commitments, that is, share * G == sum_j C_j * index^j.
+func FeldmanVerify(share *Fr, index uint64, commitments []*PointG1) bool {
+	if index == 0 || len(commitments) == 0 {
+		return false
+	}
+	g := NewG1(NewFp())
+	points := append(append([]*PointG1{}, commitments...), &G1One)
+	res, err := g.MultiExp(&PointG1{}, points, verificationScalars(index, len(commitments), share))
+	return err == nil && g.IsZero(res)
+}
+
+// FeldmanVerifyG2 is FeldmanVerify with commitments in G2.
+func FeldmanVerifyG2(share *Fr, index uint64, commitments []*PointG2) bool {
+	if index == 0 || len(commitments) == 0 {
+		return false
+	}
+	g := NewG2(NewFp2(NewFp()))
+	points := append(append([]*PointG2{}, commitments...), &G2One)
+	res, err := g.MultiExp(&PointG2{}, points, verificationScalars(index, len(commitments), share))
+	return err == nil && g.IsZero(res)
+}
+
+// PedersenShare splits secret into n shares with threshold t along with
+// blinding shares of a random polynomial and returns commitments
+// a_j * G + b_j * H to coefficients of both polynomials where H is
+// PedersenGenerator. Unlike Feldman commitments these do not reveal anything
+// about the secret.
+func PedersenShare(secret *Fr, t, n int, r io.Reader) (shares, blindings []*Fr, commitments []*PointG1, err error) {
+	a, b, shares, blindings, err := sharePedersenPolynomials(secret, t, n, r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	g := NewG1(NewFp())
+	h := PedersenGenerator()
+	commitments = make([]*PointG1, t)
+	for j := 0; j < t; j++ {
+		c, err := g.MultiExp(&PointG1{}, []*PointG1{&G1One, h}, []*big.Int{a[j].ToBig(), b[j].ToBig()})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		commitments[j] = c
+	}
+	return shares, blindings, commitments, nil
+}
+
+// PedersenShareG2 is PedersenShare with commitments in G2 where H is
+// PedersenGeneratorG2.
+func PedersenShareG2(secret *Fr, t, n int, r io.Reader) (shares, blindings []*Fr, commitments []*PointG2, err error) {
+	a, b, shares, blindings, err := sharePedersenPolynomials(secret, t, n, r)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	g := NewG2(NewFp2(NewFp()))
+	h := PedersenGeneratorG2()
+	commitments = make([]*PointG2, t)
+	for j := 0; j < t; j++ {
+		c, err := g.MultiExp(&PointG2{}, []*PointG2{&G2One, h}, []*big.Int{a[j].ToBig(), b[j].ToBig()})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		commitments[j] = c
+	}
+	return shares, blindings, commitments, nil
+}
+
+// PedersenVerify checks share and blinding share of participant at given
+// index against Pedersen commitments, that is,
+// share * G + blinding * H == sum_j C_j * index^j.
+func PedersenVerify(share, blinding *Fr, index uint64, commitments []*PointG1) bool {
+	if index == 0 || len(commitments) == 0 {
+		return false
+	}
+	g := NewG1(NewFp())
+	points := append(append([]*PointG1{}, commitments...), &G1One, PedersenGenerator())
+	res, err := g.MultiExp(&PointG1{}, points, verificationScalars(index, len(commitments), share, blinding))
+	return err == nil && g.IsZero(res)
+}
+
+// PedersenVerifyG2 is PedersenVerify with commitments in G2.
+func PedersenVerifyG2(share, blinding *Fr, index uint64, commitments []*PointG2) bool {
+	if index == 0 || len(commitments) == 0 {
+		return false
+	}
+	g := NewG2(NewFp2(NewFp()))
+	points := append(append([]*PointG2{}, commitments...), &G2One, PedersenGeneratorG2())
+	res, err := g.MultiExp(&PointG2{}, points, verificationScalars(index, len(commitments), share, blinding))
+	return err == nil && g.IsZero(res)
+}
+
+// sharePolynomial samples a polynomial of degree t-1 with constant term
+// secret and evaluates it at indexes 1 to n.
+func sharePolynomial(secret *Fr, t, n int, r io.Reader) (coeffs, shares []*Fr, err error) {
+	if t < 1 || t > n {
+		return nil, nil, fmt.Errorf("threshold should be in range of [1, %d]", n)
+	}
+	if coeffs, err = randomPolynomial(secret, t, r); err != nil {
+		return nil, nil, err
+	}
+	shares = make([]*Fr, n)
+	for i := 0; i < n; i++ {
+		shares[i] = evalPolynomial(coeffs, uint64(i+1))
+	}
+	return coeffs, shares, nil
+}
+
+// sharePedersenPolynomials samples the secret polynomial and a blinding
+// polynomial with a random constant term and evaluates both at indexes 1 to n.
+func sharePedersenPolynomials(secret *Fr, t, n int, r io.Reader) (a, b, shares, blindings []*Fr, err error) {
+	if a, shares, err = sharePolynomial(secret, t, n, r); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	b0, err := new(Fr).Rand(r)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if b, blindings, err = sharePolynomial(b0, t, n, r); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return a, b, shares, blindings, nil
+}
+
+// verificationScalars returns scalars of the check
+// sum_j C_j * x^j - sum_k s_k * G_k == 0, powers of index for k commitments
+// followed by negated shares.
+func verificationScalars(index uint64, k int, shares ...*Fr) []*big.Int {
+	out := make([]*big.Int, 0, k+len(shares))
+	x, xj := new(Fr).SetUint64(index), new(Fr).One()
+	for j := 0; j < k; j++ {
+		out = append(out, xj.ToBig())
+		xj.Mul(xj, x)
+	}
+	neg := new(Fr)
+	for _, s := range shares {
+		neg.Neg(s)
+		out = append(out, neg.ToBig())
+	}
+	return out
+}
@@ -0,0 +1,182 @@
+package bls
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestPedersenGenerator(t *testing.T) {
+	g := NewG1(NewFp())
+	h := PedersenGenerator()
+	if !g.IsOnCurve(h) || !g.InCorrectSubgroup(h) {
+		t.Fatalf("generator is not in G1")
+	}
+	if g.IsZero(h) || g.Equal(h, &G1One) {
+		t.Fatalf("bad generator")
+	}
+	if !g.Equal(h, PedersenGenerator()) {
+		t.Fatalf("generator should be deterministic")
+	}
+	// returned generator is a copy
+	g.Double(h, h)
+	if g.Equal(h, PedersenGenerator()) {
+		t.Fatalf("generator should not be shared")
+	}
+	g2 := NewG2(NewFp2(NewFp()))
+	h2 := PedersenGeneratorG2()
+	if !g2.IsOnCurve(h2) || !g2.InCorrectSubgroup(h2) {
+		t.Fatalf("generator is not in G2")
+	}
+	if g2.IsZero(h2) || g2.Equal(h2, &G2One) {
+		t.Fatalf("bad G2 generator")
+	}
+}
+
+func TestFeldmanVSS(t *testing.T) {
+	g := NewG1(NewFp())
+	one := new(Fr).One()
+	for i := 0; i < n; i++ {
+		tt, nn := 3, 5
+		secret, _ := new(Fr).Rand(rand.Reader)
+		shares, commitments, err := FeldmanShare(secret, tt, nn, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commitments) != tt {
+			t.Fatalf("expected %d commitments, got %d", tt, len(commitments))
+		}
+		if !g.Equal(commitments[0], g.MulScalar(&PointG1{}, &G1One, secret.ToBig())) {
+			t.Fatalf("first commitment should be public key")
+		}
+		for j, share := range shares {
+			index := uint64(j + 1)
+			if !FeldmanVerify(share, index, commitments) {
+				t.Fatalf("valid share is rejected")
+			}
+			if FeldmanVerify(share, index%uint64(nn)+1, commitments) {
+				t.Fatalf("share is accepted at another index")
+			}
+			bad := new(Fr)
+			bad.Add(share, one)
+			if FeldmanVerify(bad, index, commitments) {
+				t.Fatalf("bad share is accepted")
+			}
+		}
+		if FeldmanVerify(shares[0], 0, commitments) {
+			t.Fatalf("zero index is accepted")
+		}
+		s, err := RecoverSecret(shares[1:1+tt], []uint64{2, 3, 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Equal(secret) {
+			t.Fatalf("bad secret recovery")
+		}
+	}
+	if _, _, err := FeldmanShare(new(Fr), 4, 3, rand.Reader); err == nil {
+		t.Fatalf("threshold larger than number of shares is accepted")
+	}
+}
+
+func TestPedersenVSS(t *testing.T) {
+	one := new(Fr).One()
+	for i := 0; i < n; i++ {
+		tt, nn := 3, 5
+		secret, _ := new(Fr).Rand(rand.Reader)
+		shares, blindings, commitments, err := PedersenShare(secret, tt, nn, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commitments) != tt {
+			t.Fatalf("expected %d commitments, got %d", tt, len(commitments))
+		}
+		for j := range shares {
+			index := uint64(j + 1)
+			if !PedersenVerify(shares[j], blindings[j], index, commitments) {
+				t.Fatalf("valid share is rejected")
+			}
+			if PedersenVerify(shares[j], blindings[j], index%uint64(nn)+1, commitments) {
+				t.Fatalf("share is accepted at another index")
+			}
+			bad := new(Fr)
+			bad.Add(shares[j], one)
+			if PedersenVerify(bad, blindings[j], index, commitments) {
+				t.Fatalf("bad share is accepted")
+			}
+			bad.Add(blindings[j], one)
+			if PedersenVerify(shares[j], bad, index, commitments) {
+				t.Fatalf("bad blinding is accepted")
+			}
+		}
+		s, err := RecoverSecret(shares[:tt], []uint64{1, 2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Equal(secret) {
+			t.Fatalf("bad secret recovery")
+		}
+	}
+}
+
+func TestVSSG2(t *testing.T) {
+	g := NewG2(NewFp2(NewFp()))
+	one := new(Fr).One()
+	tt, nn := 3, 5
+	secret, _ := new(Fr).Rand(rand.Reader)
+	t.Run("Feldman", func(t *testing.T) {
+		shares, commitments, err := FeldmanShareG2(secret, tt, nn, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commitments) != tt {
+			t.Fatalf("expected %d commitments, got %d", tt, len(commitments))
+		}
+		if !g.Equal(commitments[0], g.MulScalar(&PointG2{}, &G2One, secret.ToBig())) {
+			t.Fatalf("first commitment should be public key")
+		}
+		for j, share := range shares {
+			index := uint64(j + 1)
+			if !FeldmanVerifyG2(share, index, commitments) {
+				t.Fatalf("valid share is rejected")
+			}
+			if FeldmanVerifyG2(share, index%uint64(nn)+1, commitments) {
+				t.Fatalf("share is accepted at another index")
+			}
+			bad := new(Fr)
+			bad.Add(share, one)
+			if FeldmanVerifyG2(bad, index, commitments) {
+				t.Fatalf("bad share is accepted")
+			}
+		}
+		if FeldmanVerifyG2(shares[0], 0, commitments) {
+			t.Fatalf("zero index is accepted")
+		}
+	})
+	t.Run("Pedersen", func(t *testing.T) {
+		shares, blindings, commitments, err := PedersenShareG2(secret, tt, nn, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range shares {
+			index := uint64(j + 1)
+			if !PedersenVerifyG2(shares[j], blindings[j], index, commitments) {
+				t.Fatalf("valid share is rejected")
+			}
+			if PedersenVerifyG2(shares[j], blindings[j], index%uint64(nn)+1, commitments) {
+				t.Fatalf("share is accepted at another index")
+			}
+			bad := new(Fr)
+			bad.Add(blindings[j], one)
+			if PedersenVerifyG2(shares[j], bad, index, commitments) {
+				t.Fatalf("bad blinding is accepted")
+			}
+		}
+		s, err := RecoverSecret(shares[:tt], []uint64{1, 2, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Equal(secret) {
+			t.Fatalf("bad secret recovery")
+		}
+	})
+}